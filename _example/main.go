@@ -18,17 +18,17 @@ func main() {
 	orderID := uuid.New().String()
 
 	r, err := c.CreateInvoice(&liqpay.InvoiceRequest{
-		Amount:        100,
+		Amount:        liqpay.NewAmount(100, 0),
 		Currency:      liqpay.CurrencyUAH,
 		Description:   "Test",
 		Email:         "test@gmail.com",
 		OrderID:       orderID,
 		Phone:         "380969696969",
 		ActionPayment: "pay",
-		ExpiredDate:   time.Now().Add(time.Hour * 5).Format(time.DateTime),
+		ExpiredDate:   liqpay.NewTime(time.Now().Add(time.Hour * 5)),
 		Goods: []liqpay.InvoiceItem{
 			{
-				Amount: 100,
+				Amount: liqpay.NewAmount(100, 0),
 				Count:  2,
 				Unit:   "pcs.",
 				Name:   "Test",
@@ -51,11 +51,11 @@ func main() {
 
 	slink, err := c.CreateSubscription(&liqpay.SubscriptionRequest{
 		OrderID:            orderID,
-		Amount:             100,
+		Amount:             liqpay.NewAmount(100, 0),
 		Currency:           liqpay.CurrencyUAH,
 		Description:        "test1",
 		Phone:              "380969696969",
-		SubscribeDateStart: time.Now().Format(time.DateTime),
+		SubscribeDateStart: liqpay.NewTime(time.Now()),
 		SubscribePeriod:    liqpay.SubscribePeriodMonthly,
 		ServerURL:          "https://2844-193-56-13-203.ngrok-free.app/callback",
 	})
@@ -67,7 +67,7 @@ func main() {
 
 	clink, err := c.CreateCheckout(&liqpay.CheckoutRequest{
 		OrderID:     orderID,
-		Amount:      100,
+		Amount:      liqpay.NewAmount(100, 0),
 		Currency:    liqpay.CurrencyUAH,
 		Description: "test1",
 		ServerURL:   "https://2844-193-56-13-203.ngrok-free.app/callback",
@@ -94,12 +94,8 @@ func main() {
 			signature = ctx.FormValue("signature")
 		)
 
-		if err := c.ValidateCallback(data, signature); err != nil {
-			return err
-		}
-
-		var callback liqpay.Callback
-		if err := ctx.BodyParser(&callback); err != nil {
+		callback, err := c.ParseCallback(data, signature)
+		if err != nil {
 			return err
 		}
 
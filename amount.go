@@ -0,0 +1,377 @@
+package liqpay
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxAmountScale is the maximum number of fractional digits an Amount can hold.
+const maxAmountScale = 9
+
+var pow10 = [...]int64{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000}
+
+// Amount is an exact decimal amount of money.
+//
+// The zero value is 0. Amounts are normalized, so equal amounts compare equal with ==.
+// Arithmetic panics if the result does not fit into the internal 64-bit representation,
+// which only happens for amounts far beyond any real payment.
+type Amount struct {
+	value int64 // Unscaled value
+	scale int8  // Number of fractional digits
+}
+
+// NewAmount creates an amount from an unscaled value and the number of fractional digits,
+// e.g. NewAmount(734, 2) is 7.34.
+func NewAmount(value int64, scale int) Amount {
+	if scale < 0 || scale > maxAmountScale {
+		panic(fmt.Sprintf("liqpay: amount scale %d is out of range", scale))
+	}
+	return Amount{value: value, scale: int8(scale)}.normalize()
+}
+
+// AmountFromMinor creates an amount from minor units of the currency, e.g. cents or kopiykas.
+func AmountFromMinor(minor int64, currency Currency) Amount {
+	return NewAmount(minor, currency.MinorUnits())
+}
+
+// AmountFromFloat converts a float64 to an amount using the shortest decimal representation
+// of the float, so AmountFromFloat(7.34) is exactly 7.34. Digits beyond 9 fractional digits are rounded.
+func AmountFromFloat(f float64) (Amount, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Amount{}, fmt.Errorf("liqpay: invalid amount %v", f)
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > maxAmountScale {
+		s = strconv.FormatFloat(f, 'f', maxAmountScale, 64)
+	}
+
+	return ParseAmount(s)
+}
+
+// ParseAmount parses a decimal amount such as "5", "7.34" or "-0.5".
+func ParseAmount(s string) (Amount, error) {
+	str := s
+	negative := false
+
+	switch {
+	case strings.HasPrefix(str, "-"):
+		negative = true
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	if intPart == "" && fracPart == "" {
+		return Amount{}, fmt.Errorf("liqpay: invalid amount %q", s)
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > maxAmountScale {
+		return Amount{}, fmt.Errorf("liqpay: amount %q has more than %d fractional digits", s, maxAmountScale)
+	}
+
+	var value int64
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Amount{}, fmt.Errorf("liqpay: invalid amount %q", s)
+		}
+		if value > (math.MaxInt64-int64(r-'0'))/10 {
+			return Amount{}, fmt.Errorf("liqpay: amount %q is out of range", s)
+		}
+		value = value*10 + int64(r-'0')
+	}
+
+	if negative {
+		value = -value
+	}
+
+	return Amount{value: value, scale: int8(len(fracPart))}.normalize(), nil
+}
+
+// MustParseAmount is like ParseAmount but panics if the amount cannot be parsed.
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// normalize removes trailing fractional zeros.
+func (a Amount) normalize() Amount {
+	if a.value == 0 {
+		return Amount{}
+	}
+	for a.scale > 0 && a.value%10 == 0 {
+		a.value /= 10
+		a.scale--
+	}
+	return a
+}
+
+// rescale returns the unscaled value of the amount with the given number of fractional digits.
+// The scale must not be less than the amount scale.
+func (a Amount) rescale(scale int8) int64 {
+	factor := pow10[scale-a.scale]
+	if a.value != 0 && (a.value > math.MaxInt64/factor || a.value < math.MinInt64/factor) {
+		panic("liqpay: amount overflow")
+	}
+	return a.value * factor
+}
+
+// IsZero reports whether the amount is zero.
+func (a Amount) IsZero() bool {
+	return a.value == 0
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the amount.
+func (a Amount) Sign() int {
+	switch {
+	case a.value < 0:
+		return -1
+	case a.value > 0:
+		return 1
+	}
+	return 0
+}
+
+// Cmp compares the amounts and returns -1, 0 or 1.
+func (a Amount) Cmp(b Amount) int {
+	return a.Sub(b).Sign()
+}
+
+// Equal reports whether the amounts are equal.
+func (a Amount) Equal(b Amount) bool {
+	return a == b
+}
+
+// Add returns a + b.
+func (a Amount) Add(b Amount) Amount {
+	scale := max(a.scale, b.scale)
+	x, y := a.rescale(scale), b.rescale(scale)
+	if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+		panic("liqpay: amount overflow")
+	}
+	return Amount{value: x + y, scale: scale}.normalize()
+}
+
+// Sub returns a - b.
+func (a Amount) Sub(b Amount) Amount {
+	return a.Add(b.Neg())
+}
+
+// Neg returns -a.
+func (a Amount) Neg() Amount {
+	if a.value == math.MinInt64 {
+		panic("liqpay: amount overflow")
+	}
+	return Amount{value: -a.value, scale: a.scale}
+}
+
+// Mul returns the amount multiplied by n, e.g. a unit price by a quantity.
+func (a Amount) Mul(n int64) Amount {
+	// The division check misses MinInt64 * -1, which overflows back to MinInt64.
+	if a.value != 0 && n != 0 && ((a.value*n)/n != a.value || (a.value == math.MinInt64 && n == -1)) {
+		panic("liqpay: amount overflow")
+	}
+	return Amount{value: a.value * n, scale: a.scale}.normalize()
+}
+
+// Convert returns the amount converted at the exchange rate and rounded half away from zero
+// to minor units of the currency, e.g. a payment amount to the card currency of a DCC offer.
+func (a Amount) Convert(rate Amount, currency Currency) Amount {
+	places := int8(currency.MinorUnits())
+
+	// The product has a.scale + rate.scale fractional digits.
+	product := new(big.Int).Mul(big.NewInt(a.value), big.NewInt(rate.value))
+	shift := int64(a.scale) + int64(rate.scale) - int64(places)
+	if shift < 0 {
+		product.Mul(product, big.NewInt(pow10[-shift]))
+		shift = 0
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil)
+	value, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	if remainder.Lsh(remainder.Abs(remainder), 1).Cmp(divisor) >= 0 {
+		value.Add(value, big.NewInt(int64(product.Sign())))
+	}
+	if !value.IsInt64() {
+		panic("liqpay: amount overflow")
+	}
+
+	return Amount{value: value.Int64(), scale: places}.normalize()
+}
+
+// Round rounds the amount to the given number of fractional digits, half away from zero.
+// Places beyond the amount scale leave it unchanged.
+func (a Amount) Round(places int) Amount {
+	// Comparing before the conversion keeps places that do not fit into int8 from wrapping around.
+	if places < 0 || places >= int(a.scale) {
+		return a
+	}
+
+	factor := pow10[a.scale-int8(places)]
+	value, remainder := a.value/factor, a.value%factor
+	if remainder*2 >= factor {
+		value++
+	} else if remainder*2 <= -factor {
+		value--
+	}
+
+	return Amount{value: value, scale: int8(places)}.normalize()
+}
+
+// MinorUnits returns the amount in minor units of the currency, e.g. cents or kopiykas.
+// It fails if the amount has more fractional digits than the currency allows.
+func (a Amount) MinorUnits(currency Currency) (int64, error) {
+	if err := a.Validate(currency); err != nil {
+		return 0, err
+	}
+	return a.rescale(int8(currency.MinorUnits())), nil
+}
+
+// Validate checks that the amount has no more fractional digits than the currency allows.
+func (a Amount) Validate(currency Currency) error {
+	if int(a.scale) > currency.MinorUnits() {
+		return fmt.Errorf("liqpay: amount %s has more than %d fractional digits allowed for %s", a, currency.MinorUnits(), currency)
+	}
+	return nil
+}
+
+// Float64 returns the nearest float64 value of the amount.
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// String returns the amount in decimal notation without trailing zeros, e.g. "7.34" or "5".
+func (a Amount) String() string {
+	if a.scale == 0 {
+		return strconv.FormatInt(a.value, 10)
+	}
+	return a.StringFixed(int(a.scale))
+}
+
+// StringFixed returns the amount in decimal notation with exactly the given number of fractional digits,
+// rounding half away from zero if needed, e.g. "7.30".
+func (a Amount) StringFixed(places int) string {
+	a = a.Round(places)
+
+	negative := a.value < 0
+	value := a.value
+	if negative {
+		value = -value
+	}
+
+	digits := strconv.FormatUint(uint64(value), 10)
+	if places > int(a.scale) {
+		digits += strings.Repeat("0", places-int(a.scale))
+	}
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+
+	s := digits
+	if places > 0 {
+		s = digits[:len(digits)-places] + "." + digits[len(digits)-places:]
+	}
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+// MarshalJSON encodes the amount as a JSON number.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes the amount from a JSON number or a string.
+// JSON numbers with more than 9 fractional digits, such as float artifacts like 0.11000000000000001,
+// are rounded half away from zero. Strings are parsed as strictly as with ParseAmount.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		if unquoted == "" {
+			*a = Amount{}
+			return nil
+		}
+		parsed, err := ParseAmount(unquoted)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	}
+
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("liqpay: invalid amount %s", data)
+		}
+		parsed, err := AmountFromFloat(f)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	}
+
+	parsed, err := parseRoundedAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// parseRoundedAmount is like ParseAmount but rounds digits beyond 9 fractional digits half away from zero.
+func parseRoundedAmount(s string) (Amount, error) {
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if len(fracPart) <= maxAmountScale {
+		return ParseAmount(s)
+	}
+
+	rest := fracPart[maxAmountScale:]
+	if strings.Trim(rest, "0123456789") != "" {
+		return Amount{}, fmt.Errorf("liqpay: invalid amount %q", s)
+	}
+
+	a, err := ParseAmount(intPart + "." + fracPart[:maxAmountScale])
+	if err != nil {
+		return Amount{}, err
+	}
+	if rest[0] >= '5' {
+		ulp := NewAmount(1, maxAmountScale)
+		if strings.HasPrefix(s, "-") {
+			ulp = ulp.Neg()
+		}
+		a = a.Add(ulp)
+	}
+	return a, nil
+}
+
+// currencyMinorUnits is the number of fractional digits of each currency (ISO 4217 exponent).
+var currencyMinorUnits = map[Currency]int{
+	CurrencyUAH: 2,
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+}
+
+// MinorUnits returns the number of fractional digits of the currency. Unknown currencies have 2.
+func (c Currency) MinorUnits() int {
+	if units, ok := currencyMinorUnits[c]; ok {
+		return units
+	}
+	return 2
+}
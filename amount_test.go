@@ -0,0 +1,224 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "5", want: "5"},
+		{in: "7.34", want: "7.34"},
+		{in: "7.30", want: "7.3"},
+		{in: "-0.5", want: "-0.5"},
+		{in: "+1.10", want: "1.1"},
+		{in: ".5", want: "0.5"},
+		{in: "0.000000001", want: "0.000000001"},
+		{in: "0.000", want: "0"},
+		{in: "9223372036854775807", want: "9223372036854775807"},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1e5", wantErr: true},
+		{in: "1.0000000001", wantErr: true},
+		{in: "9223372036854775808", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseAmount(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAmount(%q) error = %v, want error %t", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseAmount(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountRound(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int
+		want   string
+	}{
+		{in: "7.345", places: 2, want: "7.35"},
+		{in: "7.344", places: 2, want: "7.34"},
+		{in: "-7.345", places: 2, want: "-7.35"},
+		{in: "-7.344", places: 2, want: "-7.34"},
+		{in: "0.5", places: 0, want: "1"},
+		{in: "-0.5", places: 0, want: "-1"},
+		{in: "7.3", places: 2, want: "7.3"},
+		{in: "7.345", places: -1, want: "7.345"},
+		{in: "7.345", places: 300, want: "7.345"},
+		{in: "0.123456789", places: 129, want: "0.123456789"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseAmount(tt.in).Round(tt.places)
+		if got.String() != tt.want {
+			t.Errorf("%s.Round(%d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestAmountStringFixed(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int
+		want   string
+	}{
+		{in: "7.3", places: 2, want: "7.30"},
+		{in: "7.345", places: 2, want: "7.35"},
+		{in: "-0.05", places: 2, want: "-0.05"},
+		{in: "0.004", places: 2, want: "0.00"},
+		{in: "5", places: 0, want: "5"},
+		{in: "1.5", places: 0, want: "2"},
+		{in: "1.5", places: 200, want: "1.5" + strings.Repeat("0", 199)},
+	}
+
+	for _, tt := range tests {
+		if got := MustParseAmount(tt.in).StringFixed(tt.places); got != tt.want {
+			t.Errorf("%s.StringFixed(%d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestAmountConvert(t *testing.T) {
+	tests := []struct {
+		amount   string
+		rate     string
+		currency Currency
+		want     string
+	}{
+		{amount: "100", rate: "0.0245", currency: CurrencyUSD, want: "2.45"},
+		{amount: "100.01", rate: "0.0245", currency: CurrencyUSD, want: "2.45"},
+		{amount: "10.1", rate: "0.05", currency: CurrencyUSD, want: "0.51"},
+		{amount: "-10.1", rate: "0.05", currency: CurrencyUSD, want: "-0.51"},
+		{amount: "2.45", rate: "40.8163", currency: CurrencyUAH, want: "100"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseAmount(tt.amount).Convert(MustParseAmount(tt.rate), tt.currency)
+		if got.String() != tt.want {
+			t.Errorf("%s.Convert(%s, %s) = %s, want %s", tt.amount, tt.rate, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestAmountOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() Amount
+	}{
+		{name: "add", fn: func() Amount { return NewAmount(math.MaxInt64, 0).Add(NewAmount(1, 0)) }},
+		{name: "add rescaled", fn: func() Amount { return NewAmount(math.MaxInt64/10+1, 0).Add(NewAmount(1, 1)) }},
+		{name: "mul", fn: func() Amount { return NewAmount(math.MaxInt64/2+1, 0).Mul(2) }},
+		{name: "mul min by -1", fn: func() Amount { return NewAmount(math.MinInt64, 0).Mul(-1) }},
+		{name: "neg min", fn: func() Amount { return NewAmount(math.MinInt64, 0).Neg() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", tt.name)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `7.34`, want: "7.34"},
+		{in: `"7.34"`, want: "7.34"},
+		{in: `""`, want: "0"},
+		{in: `null`, want: "0"},
+		{in: `1e2`, want: "100"},
+		{in: `1.5E-3`, want: "0.0015"},
+		{in: `0.11000000000000001`, want: "0.11"},
+		{in: `7.3399999999999999`, want: "7.34"},
+		{in: `-0.0000000015`, want: "-0.000000002"},
+		{in: `0.1234567894`, want: "0.123456789"},
+		{in: `"0.11000000000000001"`, wantErr: true},
+		{in: `"abc"`, wantErr: true},
+		{in: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Amount
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, want error %t", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Unmarshal(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal(%s) error = %v", got, err)
+			}
+			if string(encoded) != tt.want {
+				t.Errorf("Marshal(%s) = %s, want %s", got, encoded, tt.want)
+			}
+		})
+	}
+}
+
+func TestInjectMissingKeysKeepsExactAmounts(t *testing.T) {
+	c := client{config: NewConfig("public", "private", false)}
+
+	tests := []string{"0.1", "7.34", "92233720368.547758", "9007199254740993"}
+	for _, amount := range tests {
+		data, err := c.injectMissingKeys(&RefundRequest{Action: ActionRefund, OrderID: "order-1", Amount: MustParseAmount(amount)})
+		if err != nil {
+			t.Fatalf("injectMissingKeys() error = %v", err)
+		}
+
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if want := `"amount":` + amount + `,`; !strings.Contains(string(encoded), want) {
+			t.Errorf("injectMissingKeys() = %s, want it to contain %s", encoded, want)
+		}
+	}
+}
+
+func TestAmountDecodeFloatArtifacts(t *testing.T) {
+	body := `{"status":"success","amount":0.11000000000000001,"receiver_commission":0.0016500000000000002,"amount_debit":2.9999999999999996}`
+
+	var got StatusResponse
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	for name, tt := range map[string]struct{ got, want Amount }{
+		"amount":              {got.Amount, MustParseAmount("0.11")},
+		"receiver_commission": {got.ReceiverCommission, MustParseAmount("0.00165")},
+		"amount_debit":        {got.AmountDebit, MustParseAmount("3")},
+	} {
+		if !tt.got.Equal(tt.want) {
+			t.Errorf("%s = %s, want %s", name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package liqpay
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInvalidCallbackSignature is returned when the callback signature does not match the callback data.
+// It matches ErrInvalidSignature with errors.Is.
+var ErrInvalidCallbackSignature error = &callbackSignatureError{}
+
+type callbackSignatureError struct{}
+
+func (e *callbackSignatureError) Error() string {
+	return "liqpay client: callback signature verification failed"
+}
+
+func (e *callbackSignatureError) Is(target error) bool {
+	return target == ErrInvalidSignature
+}
+
+// ParseCallback validates the callback signature and decodes the callback data.
+func (c client) ParseCallback(data string, signature string) (*Callback, error) {
+	if err := c.ValidateCallback(data, signature); err != nil {
+		return nil, err
+	}
+
+	decodedData, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to decode data: %w", err)
+	}
+
+	callback := &Callback{}
+	if err := json.Unmarshal(decodedData, callback); err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to unmarshal callback: %w", err)
+	}
+
+	return callback, nil
+}
+
+// CallbackHandlerFunc handles a verified and decoded LiqPay callback.
+// Returning an error makes the handler respond with 500 Internal Server Error.
+type CallbackHandlerFunc func(ctx context.Context, callback *Callback) error
+
+// CallbackHandler is an http.Handler for LiqPay callbacks (server_url notifications).
+//
+// It responds with:
+//   - 405 Method Not Allowed for non-POST requests;
+//   - 400 Bad Request if the form or the callback data is malformed;
+//   - 403 Forbidden if the signature verification fails;
+//   - 500 Internal Server Error if the handler func returns an error;
+//   - 200 OK otherwise.
+type CallbackHandler struct {
+	client Client
+	fn     CallbackHandlerFunc
+}
+
+// NewCallbackHandler creates a new CallbackHandler that verifies callbacks with the provided client
+// and dispatches them to fn.
+func NewCallbackHandler(c Client, fn CallbackHandlerFunc) *CallbackHandler {
+	return &CallbackHandler{client: c, fn: fn}
+}
+
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		data      = r.PostForm.Get("data")
+		signature = r.PostForm.Get("signature")
+	)
+
+	if data == "" || signature == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	callback, err := h.client.ParseCallback(data, signature)
+	switch {
+	case errors.Is(err, ErrInvalidCallbackSignature):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.fn(r.Context(), callback); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,162 @@
+package liqpay
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// signedCallback encodes the callback JSON and signs it with the algorithm and the "private" key.
+func signedCallback(t *testing.T, algorithm SignatureAlgorithm, payload string) (data, signature string) {
+	t.Helper()
+
+	data = base64.StdEncoding.EncodeToString([]byte(payload))
+	signature, err := client{config: NewConfig("public", "private", false)}.signWith(algorithm, []byte(data))
+	if err != nil {
+		t.Fatalf("signWith() error = %v", err)
+	}
+	return data, signature
+}
+
+const testCallback = `{"action":"pay","payment_id":1000001,"status":"success","order_id":"order-1","amount":7.34,` +
+	`"currency":"UAH","mpi_eci":7,"sender_card_country":"804","create_date":1461493815000}`
+
+func TestParseCallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		accepted   []SignatureAlgorithm
+		algorithm  SignatureAlgorithm
+		payload    string
+		tamper     func(data string) string
+		wantErr    bool
+		wantSigErr bool
+	}{
+		{name: "sha1", accepted: []SignatureAlgorithm{SignatureAlgorithmSHA1, SignatureAlgorithmSHA3256}, algorithm: SignatureAlgorithmSHA1, payload: testCallback},
+		{name: "sha3-256", accepted: []SignatureAlgorithm{SignatureAlgorithmSHA1, SignatureAlgorithmSHA3256}, algorithm: SignatureAlgorithmSHA3256, payload: testCallback},
+		{name: "default algorithm", algorithm: SignatureAlgorithmSHA1, payload: testCallback},
+		{
+			name:       "algorithm not accepted",
+			accepted:   []SignatureAlgorithm{SignatureAlgorithmSHA3256},
+			algorithm:  SignatureAlgorithmSHA1,
+			payload:    testCallback,
+			wantErr:    true,
+			wantSigErr: true,
+		},
+		{
+			name:      "tampered data",
+			algorithm: SignatureAlgorithmSHA1,
+			payload:   testCallback,
+			tamper: func(data string) string {
+				decoded, _ := base64.StdEncoding.DecodeString(data)
+				return base64.StdEncoding.EncodeToString([]byte(strings.Replace(string(decoded), "7.34", "734", 1)))
+			},
+			wantErr:    true,
+			wantSigErr: true,
+		},
+		{name: "malformed json", algorithm: SignatureAlgorithmSHA1, payload: `{"status":`, wantErr: true},
+		{
+			name:      "malformed base64",
+			algorithm: SignatureAlgorithmSHA1,
+			payload:   testCallback,
+			tamper:    func(data string) string { return "%" + data },
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig("public", "private", false)
+			cfg.CallbackSignatureAlgorithms = tt.accepted
+			c := NewClient(cfg, nil)
+
+			data, signature := signedCallback(t, tt.algorithm, tt.payload)
+			if tt.tamper != nil {
+				data = tt.tamper(data)
+			}
+
+			callback, err := c.ParseCallback(data, signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCallback() error = %v, want error %t", err, tt.wantErr)
+			}
+			if got := errors.Is(err, ErrInvalidCallbackSignature); got != tt.wantSigErr {
+				t.Errorf("errors.Is(%v, ErrInvalidCallbackSignature) = %t, want %t", err, got, tt.wantSigErr)
+			}
+			if got := errors.Is(err, ErrInvalidSignature); got != tt.wantSigErr {
+				t.Errorf("errors.Is(%v, ErrInvalidSignature) = %t, want %t", err, got, tt.wantSigErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if callback.OrderID != "order-1" || callback.Status != StatusSuccess || callback.Amount.String() != "7.34" {
+				t.Errorf("ParseCallback() = %s %s %s, want order-1 success 7.34", callback.OrderID, callback.Status, callback.Amount)
+			}
+			if callback.MpiEci != 7 || callback.SenderCardCountry != "804" || callback.CreateDate.UnixMilli() != 1461493815000 {
+				t.Errorf("ParseCallback() = %d %q %v, want 7 \"804\" 1461493815000", callback.MpiEci, callback.SenderCardCountry, callback.CreateDate)
+			}
+		})
+	}
+}
+
+func TestCallbackHandler(t *testing.T) {
+	valid, validSignature := signedCallback(t, SignatureAlgorithmSHA1, testCallback)
+	malformed, malformedSignature := signedCallback(t, SignatureAlgorithmSHA1, `{"status":`)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		handlerErr error
+		wantCode   int
+		wantCalled bool
+	}{
+		{name: "get", method: http.MethodGet, wantCode: http.StatusMethodNotAllowed},
+		{name: "missing data", method: http.MethodPost, body: url.Values{"signature": {validSignature}}.Encode(), wantCode: http.StatusBadRequest},
+		{name: "missing signature", method: http.MethodPost, body: url.Values{"data": {valid}}.Encode(), wantCode: http.StatusBadRequest},
+		{name: "malformed form", method: http.MethodPost, body: "data=%zz", wantCode: http.StatusBadRequest},
+		{name: "malformed callback", method: http.MethodPost, body: url.Values{"data": {malformed}, "signature": {malformedSignature}}.Encode(), wantCode: http.StatusBadRequest},
+		{name: "invalid signature", method: http.MethodPost, body: url.Values{"data": {valid}, "signature": {malformedSignature}}.Encode(), wantCode: http.StatusForbidden},
+		{
+			name:       "handler error",
+			method:     http.MethodPost,
+			body:       url.Values{"data": {valid}, "signature": {validSignature}}.Encode(),
+			handlerErr: errors.New("database is down"),
+			wantCode:   http.StatusInternalServerError,
+			wantCalled: true,
+		},
+		{name: "ok", method: http.MethodPost, body: url.Values{"data": {valid}, "signature": {validSignature}}.Encode(), wantCode: http.StatusOK, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called *Callback
+			h := NewCallbackHandler(NewClient(NewConfig("public", "private", false), nil), func(ctx context.Context, callback *Callback) error {
+				called = callback
+				return tt.handlerErr
+			})
+
+			r := httptest.NewRequest(tt.method, "/liqpay/callback", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %d, want %d", w.Code, tt.wantCode)
+			}
+			if (called != nil) != tt.wantCalled {
+				t.Errorf("handler called = %t, want %t", called != nil, tt.wantCalled)
+			}
+			if called != nil && called.OrderID != "order-1" {
+				t.Errorf("handler callback order_id = %q, want order-1", called.OrderID)
+			}
+			if tt.method != http.MethodPost && w.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Allow = %q, want %q", w.Header().Get("Allow"), http.MethodPost)
+			}
+		})
+	}
+}
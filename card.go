@@ -0,0 +1,185 @@
+package liqpay
+
+import (
+	"context"
+	"errors"
+)
+
+// PaymentStep is the next step of a server-server payment.
+type PaymentStep string
+
+const (
+	PaymentStepDone     PaymentStep = "done"     // No customer action is required, see the payment status
+	PaymentStep3DS      PaymentStep = "3ds"      // Redirect the customer to RedirectTo for 3DS verification
+	PaymentStepOTP      PaymentStep = "otp"      // Submit the OTP password sent to the customer with ConfirmOTP
+	PaymentStepCVV      PaymentStep = "cvv"      // Submit the card CVV with ConfirmCVV
+	PaymentStepDCC      PaymentStep = "dcc"      // Show the conversion offer to the customer and submit the choice with ConfirmDCC
+	PaymentStepRedirect PaymentStep = "redirect" // Redirect the customer to RedirectTo
+)
+
+// PaymentStepResult is the result of a server-server payment call and describes what has to be done next.
+//
+// After the 3DS and redirect steps the customer returns to the result_url, and the final status is
+// delivered to the server_url callback and can be polled with Status or WaitForStatus.
+type PaymentStepResult struct {
+	Step       PaymentStep          // Next step of the payment
+	RedirectTo string               // URL to redirect the customer to, for the 3DS and redirect steps
+	Token      string               // Token to submit with ConfirmOTP, ConfirmCVV or ConfirmDCC
+	DCC        *DCCOffer            // Conversion offer, for the dcc step
+	Response   *CardPaymentResponse // Response of the last call
+}
+
+// newPaymentStepResult determines the next payment step from the payment response.
+func newPaymentStepResult(v *CardPaymentResponse) *PaymentStepResult {
+	result := &PaymentStepResult{
+		Step:       PaymentStepDone,
+		RedirectTo: v.RedirectTo,
+		Token:      v.Token,
+		Response:   v,
+	}
+
+	switch {
+	case v.Status == Status3DSVerify:
+		result.Step = PaymentStep3DS
+	case v.Status == StatusOTPVerify:
+		result.Step = PaymentStepOTP
+	case v.Status == StatusCVVVerify:
+		result.Step = PaymentStepCVV
+	case v.Status == StatusDCCVerify:
+		result.Step = PaymentStepDCC
+		result.DCC = newDCCOffer(v)
+	case v.RedirectTo != "":
+		result.Step = PaymentStepRedirect
+	}
+
+	return result
+}
+
+// PayByCard charges a card server-server. The payment may require further steps, see PaymentStepResult.
+func (c client) PayByCard(data *CardPaymentRequest) (*PaymentStepResult, error) {
+	return c.PayByCardContext(context.Background(), data)
+}
+
+// PayByCardContext charges a card server-server using the provided context.
+func (c client) PayByCardContext(ctx context.Context, data *CardPaymentRequest) (*PaymentStepResult, error) {
+	data.Action = ActionPay
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	if data.IP == "" {
+		return nil, errors.New("liqpay client: client ip is required for card payments")
+	}
+
+	return c.sendPaymentStep(ctx, data)
+}
+
+// PayWithApplePay charges an Apple Pay wallet server-server with the payment data of the PKPaymentToken.
+// The payment may require further steps, see PaymentStepResult.
+func (c client) PayWithApplePay(data *WalletPaymentRequest) (*PaymentStepResult, error) {
+	return c.PayWithApplePayContext(context.Background(), data)
+}
+
+// PayWithApplePayContext charges an Apple Pay wallet server-server using the provided context.
+func (c client) PayWithApplePayContext(ctx context.Context, data *WalletPaymentRequest) (*PaymentStepResult, error) {
+	data.PayType = PayTypeApplePay
+	return c.payWithWallet(ctx, data)
+}
+
+// PayWithGooglePay charges a Google Pay wallet server-server with the payment method token.
+// The payment may require further steps, see PaymentStepResult.
+func (c client) PayWithGooglePay(data *WalletPaymentRequest) (*PaymentStepResult, error) {
+	return c.PayWithGooglePayContext(context.Background(), data)
+}
+
+// PayWithGooglePayContext charges a Google Pay wallet server-server using the provided context.
+func (c client) PayWithGooglePayContext(ctx context.Context, data *WalletPaymentRequest) (*PaymentStepResult, error) {
+	data.PayType = PayTypeGooglePay
+	return c.payWithWallet(ctx, data)
+}
+
+func (c client) payWithWallet(ctx context.Context, data *WalletPaymentRequest) (*PaymentStepResult, error) {
+	data.Action = ActionPay
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	if len(data.PaymentData) == 0 {
+		return nil, errors.New("liqpay client: wallet payment data is required")
+	}
+
+	if data.IP == "" {
+		return nil, errors.New("liqpay client: client ip is required for wallet payments")
+	}
+
+	return c.sendPaymentStep(ctx, data)
+}
+
+// ConfirmOTP confirms a payment in the otp step with the OTP password sent to the customer.
+func (c client) ConfirmOTP(token string, otp string) (*PaymentStepResult, error) {
+	return c.ConfirmOTPContext(context.Background(), token, otp)
+}
+
+// ConfirmOTPContext confirms a payment with the OTP password using the provided context.
+func (c client) ConfirmOTPContext(ctx context.Context, token string, otp string) (*PaymentStepResult, error) {
+	if token == "" {
+		return nil, errors.New("liqpay client: payment token is required")
+	}
+
+	return c.sendPaymentStep(ctx, &OTPConfirmRequest{Action: ActionConfirm, Token: token, OTP: otp})
+}
+
+// ConfirmCVV confirms a payment in the cvv step with the card CVV.
+func (c client) ConfirmCVV(token string, cvv string) (*PaymentStepResult, error) {
+	return c.ConfirmCVVContext(context.Background(), token, cvv)
+}
+
+// ConfirmCVVContext confirms a payment with the card CVV using the provided context.
+func (c client) ConfirmCVVContext(ctx context.Context, token string, cvv string) (*PaymentStepResult, error) {
+	if token == "" {
+		return nil, errors.New("liqpay client: payment token is required")
+	}
+
+	return c.sendPaymentStep(ctx, &CVVConfirmRequest{Action: ActionCVV, Token: token, CVV: cvv})
+}
+
+// ConfirmDCC submits the customer's choice for a payment in the dcc step: accept to pay in the card currency
+// at the offered rate, or decline to pay in the payment currency with the conversion by the card issuer.
+func (c client) ConfirmDCC(token string, accept bool) (*PaymentStepResult, error) {
+	return c.ConfirmDCCContext(context.Background(), token, accept)
+}
+
+// ConfirmDCCContext submits the customer's choice for the conversion offer using the provided context.
+func (c client) ConfirmDCCContext(ctx context.Context, token string, accept bool) (*PaymentStepResult, error) {
+	if token == "" {
+		return nil, errors.New("liqpay client: payment token is required")
+	}
+
+	dcc := "N"
+	if accept {
+		dcc = "Y"
+	}
+
+	return c.sendPaymentStep(ctx, &DCCConfirmRequest{Action: ActionConfirm, Token: token, DCC: dcc})
+}
+
+// sendPaymentStep sends a server-server payment request and determines the next payment step from its response.
+func (c client) sendPaymentStep(ctx context.Context, data any) (*PaymentStepResult, error) {
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &CardPaymentResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return newPaymentStepResult(v), err
+	case err != nil:
+		return nil, err
+	}
+
+	return newPaymentStepResult(v), nil
+}
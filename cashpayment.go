@@ -0,0 +1,35 @@
+package liqpay
+
+import "context"
+
+// CreateCashPayment creates a payment to be paid in cash at a self-service terminal with the returned payment code.
+// The payment stays in the cash_wait status until the customer pays or the code expires.
+// Shops that are not allowed to accept cash get the err_payment_cash_acq error.
+func (c client) CreateCashPayment(data *CashPaymentRequest) (*CashPaymentResponse, error) {
+	return c.CreateCashPaymentContext(context.Background(), data)
+}
+
+// CreateCashPaymentContext creates a payment to be paid in cash using the provided context.
+func (c client) CreateCashPaymentContext(ctx context.Context, data *CashPaymentRequest) (*CashPaymentResponse, error) {
+	data.Action = ActionPayCash
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &CashPaymentResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package liqpay
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/url"
+)
+
+var checkoutFormTemplate = template.Must(template.New("checkout").Parse(
+	`<form method="POST" action="{{.URL}}" accept-charset="utf-8">` +
+		`<input type="hidden" name="data" value="{{.Data}}" />` +
+		`<input type="hidden" name="signature" value="{{.Signature}}" />` +
+		`<noscript><button type="submit">Pay</button></noscript>` +
+		`</form>` +
+		`<script>document.currentScript.previousElementSibling.submit();</script>`,
+))
+
+// CheckoutForm represents a signed checkout payload built without calling LiqPay API.
+type CheckoutForm struct {
+	Data      string        // Base64-encoded JSON payload
+	Signature string        // Signature of the payload
+	URL       string        // Checkout page URL with data and signature in the query string
+	HTML      template.HTML // Auto-submitting form that posts the payload to the checkout page
+}
+
+// BuildCheckout builds a signed checkout payload, a checkout URL and an HTML form without any network calls.
+func (c client) BuildCheckout(data *CheckoutRequest) (*CheckoutForm, error) {
+	data.Action = ActionPay
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := validateSplitRules(data.Amount, data.SplitRules); err != nil {
+		return nil, err
+	}
+
+	return c.buildCheckoutForm(data)
+}
+
+// BuildSubscription builds a signed subscription checkout payload, a checkout URL and an HTML form without any network calls.
+func (c client) BuildSubscription(data *SubscriptionRequest) (*CheckoutForm, error) {
+	data.Action = ActionSubscribe
+	data.Subscribe = "1"
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	return c.buildCheckoutForm(data)
+}
+
+// buildCheckoutForm signs the payload and renders it as a checkout URL and an HTML form.
+func (c client) buildCheckoutForm(payload any) (*CheckoutForm, error) {
+	formData, _, err := c.signPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	checkoutURL, err := url.Parse(c.config.clientServerURL())
+	if err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to parse checkout url: %w", err)
+	}
+	checkoutURL.RawQuery = formData.Encode()
+
+	form := &CheckoutForm{
+		Data:      formData.Get("data"),
+		Signature: formData.Get("signature"),
+		URL:       checkoutURL.String(),
+	}
+
+	var buf bytes.Buffer
+	err = checkoutFormTemplate.Execute(&buf, struct {
+		URL       string
+		Data      string
+		Signature string
+	}{
+		URL:       c.config.clientServerURL(),
+		Data:      form.Data,
+		Signature: form.Signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to render checkout form: %w", err)
+	}
+	form.HTML = template.HTML(buf.String())
+
+	return form, nil
+}
@@ -0,0 +1,160 @@
+package liqpay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var formAttr = regexp.MustCompile(`(action|value)="([^"]*)"`)
+
+func TestBuildCheckout(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm SignatureAlgorithm
+		build     func(c Client) (*CheckoutForm, error)
+		want      map[string]string
+	}{
+		{
+			name: "checkout",
+			build: func(c Client) (*CheckoutForm, error) {
+				return c.BuildCheckout(&CheckoutRequest{Amount: MustParseAmount("7.34"), Currency: CurrencyUAH, Description: `<b>"Order"</b> & co`, OrderID: "order-1"})
+			},
+			want: map[string]string{"action": "pay", "amount": "7.34", "currency": "UAH", "description": `<b>"Order"</b> & co`, "order_id": "order-1"},
+		},
+		{
+			name:      "checkout signed with sha3-256",
+			algorithm: SignatureAlgorithmSHA3256,
+			build: func(c Client) (*CheckoutForm, error) {
+				return c.BuildCheckout(&CheckoutRequest{Amount: MustParseAmount("5"), Currency: CurrencyUSD, Description: "Test", OrderID: "order-1"})
+			},
+			want: map[string]string{"action": "pay", "amount": "5", "currency": "USD", "order_id": "order-1"},
+		},
+		{
+			name: "subscription",
+			build: func(c Client) (*CheckoutForm, error) {
+				return c.BuildSubscription(&SubscriptionRequest{
+					Amount:             MustParseAmount("100"),
+					Currency:           CurrencyUAH,
+					Description:        "Monthly",
+					OrderID:            "order-1",
+					SubscribeDateStart: NewTime(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+					SubscribePeriod:    SubscribePeriodMonthly,
+				})
+			},
+			want: map[string]string{"action": "subscribe", "amount": "100", "subscribe": "1", "subscribe_date_start": "2024-05-01 00:00:00", "subscribe_periodicity": "month"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig("public", "private", false)
+			cfg.SignatureAlgorithm = tt.algorithm
+			c := NewClient(cfg, nil)
+
+			form, err := tt.build(c)
+			if err != nil {
+				t.Fatalf("build error = %v", err)
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(form.Data)
+			if err != nil {
+				t.Fatalf("data is not base64: %v", err)
+			}
+			var payload map[string]any
+			decoder := json.NewDecoder(strings.NewReader(string(decoded)))
+			decoder.UseNumber()
+			if err := decoder.Decode(&payload); err != nil {
+				t.Fatalf("data is not json: %v", err)
+			}
+
+			tt.want["public_key"] = "public"
+			tt.want["version"] = CurrentAPIVersion
+			for key, want := range tt.want {
+				if got, ok := payload[key]; !ok || fmt.Sprint(got) != want {
+					t.Errorf("data[%s] = %v, want %v", key, got, want)
+				}
+			}
+
+			if err := c.ValidateCallback(form.Data, form.Signature); err != nil {
+				t.Errorf("signature does not match the data: %v", err)
+			}
+
+			checkoutURL, err := url.Parse(form.URL)
+			if err != nil {
+				t.Fatalf("URL error = %v", err)
+			}
+			if base := checkoutURL.Scheme + "://" + checkoutURL.Host + checkoutURL.Path; base != ClientServerURL {
+				t.Errorf("URL = %s, want %s", base, ClientServerURL)
+			}
+			if q := checkoutURL.Query(); q.Get("data") != form.Data || q.Get("signature") != form.Signature {
+				t.Errorf("URL query = %v, want the form data and signature", q)
+			}
+
+			attrs := formAttr.FindAllStringSubmatch(string(form.HTML), -1)
+			if len(attrs) != 3 {
+				t.Fatalf("HTML = %s, want a form action and two values", form.HTML)
+			}
+			for i, want := range []string{ClientServerURL, form.Data, form.Signature} {
+				if got := html.UnescapeString(attrs[i][2]); got != want {
+					t.Errorf("HTML %s = %s, want %s", attrs[i][1], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildCheckoutEscapesHTML(t *testing.T) {
+	cfg := NewConfig("public", "private", false)
+	cfg.ClientServerURL = `https://example.com/checkout?a=1&b="><script>alert(1)</script>`
+	c := NewClient(cfg, nil)
+
+	form, err := c.BuildCheckout(&CheckoutRequest{Amount: MustParseAmount("1"), Currency: CurrencyUAH, Description: "Test", OrderID: "order-1"})
+	if err != nil {
+		t.Fatalf("BuildCheckout() error = %v", err)
+	}
+
+	if strings.Contains(string(form.HTML), `"><script>alert`) {
+		t.Errorf("HTML = %s, want the form action escaped", form.HTML)
+	}
+	if !strings.Contains(string(form.HTML), `action="https://example.com/checkout?a=1&amp;b=`) {
+		t.Errorf("HTML = %s, want the & in the form action escaped", form.HTML)
+	}
+	if strings.Count(string(form.HTML), "<script>") != 1 {
+		t.Errorf("HTML = %s, want only the auto-submit script", form.HTML)
+	}
+}
+
+func TestBuildCheckoutValidation(t *testing.T) {
+	c := NewClient(NewConfig("public", "private", false), nil)
+
+	tests := []struct {
+		name string
+		req  *CheckoutRequest
+	}{
+		{name: "too many fractional digits", req: &CheckoutRequest{Amount: MustParseAmount("1.001"), Currency: CurrencyUAH, OrderID: "order-1"}},
+		{
+			name: "split rules do not sum up",
+			req: &CheckoutRequest{
+				Amount:     MustParseAmount("10"),
+				Currency:   CurrencyUAH,
+				OrderID:    "order-1",
+				SplitRules: []SplitRule{{PublicKey: "a", Amount: MustParseAmount("4")}, {PublicKey: "b", Amount: MustParseAmount("5")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.BuildCheckout(tt.req); err == nil {
+				t.Error("BuildCheckout() error = nil, want error")
+			}
+		})
+	}
+}
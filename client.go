@@ -2,30 +2,92 @@ package liqpay
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
+	"crypto/sha3"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"hash"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 )
 
+// Client is a LiqPay API client.
+//
+// Every method that calls the LiqPay API has a Context variant that accepts a context.Context used for
+// cancellation and deadlines of the underlying HTTP request. The variants without
+// a context use context.Background.
 type Client interface {
 	CreateCheckout(req *CheckoutRequest) (string, error)
+	CreateCheckoutContext(ctx context.Context, req *CheckoutRequest) (string, error)
+	CreateHoldCheckout(req *CheckoutRequest) (string, error)
+	CreateHoldCheckoutContext(ctx context.Context, req *CheckoutRequest) (string, error)
+	CompleteHold(orderID string, amount Amount) (*HoldResponse, error)
+	CompleteHoldContext(ctx context.Context, orderID string, amount Amount) (*HoldResponse, error)
+	CancelHold(orderID string) (*HoldResponse, error)
+	CancelHoldContext(ctx context.Context, orderID string) (*HoldResponse, error)
+
+	SplitPayment(req *SplitPaymentRequest) (*StatusResponse, error)
+	SplitPaymentContext(ctx context.Context, req *SplitPaymentRequest) (*StatusResponse, error)
+
+	PayWithToken(req *TokenPaymentRequest) (*TokenPaymentResponse, error)
+	PayWithTokenContext(ctx context.Context, req *TokenPaymentRequest) (*TokenPaymentResponse, error)
+
+	PayByCard(req *CardPaymentRequest) (*PaymentStepResult, error)
+	PayByCardContext(ctx context.Context, req *CardPaymentRequest) (*PaymentStepResult, error)
+	PayWithApplePay(req *WalletPaymentRequest) (*PaymentStepResult, error)
+	PayWithApplePayContext(ctx context.Context, req *WalletPaymentRequest) (*PaymentStepResult, error)
+	PayWithGooglePay(req *WalletPaymentRequest) (*PaymentStepResult, error)
+	PayWithGooglePayContext(ctx context.Context, req *WalletPaymentRequest) (*PaymentStepResult, error)
+	ConfirmOTP(token string, otp string) (*PaymentStepResult, error)
+	ConfirmOTPContext(ctx context.Context, token string, otp string) (*PaymentStepResult, error)
+	ConfirmCVV(token string, cvv string) (*PaymentStepResult, error)
+	ConfirmCVVContext(ctx context.Context, token string, cvv string) (*PaymentStepResult, error)
+	ConfirmDCC(token string, accept bool) (*PaymentStepResult, error)
+	ConfirmDCCContext(ctx context.Context, token string, accept bool) (*PaymentStepResult, error)
+
+	BuildCheckout(req *CheckoutRequest) (*CheckoutForm, error)
+	BuildSubscription(req *SubscriptionRequest) (*CheckoutForm, error)
 
 	CreateSubscription(req *SubscriptionRequest) (string, error)
+	CreateSubscriptionContext(ctx context.Context, req *SubscriptionRequest) (string, error)
 	UpdateSubscription(req *EditSubscriptionRequest) (*SubscriptionResponse, error)
+	UpdateSubscriptionContext(ctx context.Context, req *EditSubscriptionRequest) (*SubscriptionResponse, error)
 	RemoveSubscription(orderID string) (*SubscriptionResponse, error)
+	RemoveSubscriptionContext(ctx context.Context, orderID string) (*SubscriptionResponse, error)
+
+	CreateQRPayment(req *QRPaymentRequest) (*QRPaymentResponse, error)
+	CreateQRPaymentContext(ctx context.Context, req *QRPaymentRequest) (*QRPaymentResponse, error)
+	CreateCashPayment(req *CashPaymentRequest) (*CashPaymentResponse, error)
+	CreateCashPaymentContext(ctx context.Context, req *CashPaymentRequest) (*CashPaymentResponse, error)
 
 	CreateInvoice(req *InvoiceRequest) (*InvoiceResponse, error)
+	CreateInvoiceContext(ctx context.Context, req *InvoiceRequest) (*InvoiceResponse, error)
 	CancelInvoice(orderID string) (*CancelInvoiceResponse, error)
+	CancelInvoiceContext(ctx context.Context, orderID string) (*CancelInvoiceResponse, error)
 
 	Status(orderID string) (*StatusResponse, error)
-	Refund(orderID string, amount string) (*RefundResponse, error)
+	StatusContext(ctx context.Context, orderID string) (*StatusResponse, error)
+	WaitForStatus(ctx context.Context, orderID string, opts *WaitOptions) (*StatusResponse, error)
+	Refund(orderID string, amount Amount) (*RefundResponse, error)
+	RefundContext(ctx context.Context, orderID string, amount Amount) (*RefundResponse, error)
+
+	Reports(from, to time.Time) (*ReportsResponse, error)
+	ReportsContext(ctx context.Context, from, to time.Time) (*ReportsResponse, error)
+	ReportsCSV(from, to time.Time) ([]byte, error)
+	ReportsCSVContext(ctx context.Context, from, to time.Time) ([]byte, error)
+	ReportsCompensation(date time.Time) (*CompensationReportResponse, error)
+	ReportsCompensationContext(ctx context.Context, date time.Time) (*CompensationReportResponse, error)
+	ReportsCompensationCSV(date time.Time) ([]byte, error)
+	ReportsCompensationCSVContext(ctx context.Context, date time.Time) ([]byte, error)
 
 	ValidateCallback(data string, signature string) error
+	ParseCallback(data string, signature string) (*Callback, error)
 }
 
 type client struct {
@@ -35,10 +97,12 @@ type client struct {
 
 // NewClient creates a new LiqPay client with the provided configuration and HTTP client.
 func NewClient(config *Config, httpClient *http.Client) Client {
-	var httpC = http.DefaultClient
+	var httpC = *http.DefaultClient
 
+	// The HTTP client is copied so that disabling redirects does not affect
+	// other users of the same client, e.g. other LiqPay clients in the process.
 	if httpClient != nil {
-		httpC = httpClient
+		httpC = *httpClient
 	}
 
 	httpC.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -47,17 +111,32 @@ func NewClient(config *Config, httpClient *http.Client) Client {
 
 	return &client{
 		config:     config,
-		httpClient: httpC,
+		httpClient: &httpC,
 	}
 }
 
-// sign generates a signature for the given data using the client's private key.
-func (c client) sign(data []byte) string {
-	hasher := sha1.New()
+// sign generates a signature for the given data using the client's private key and signature algorithm.
+func (c client) sign(data []byte) (string, error) {
+	return c.signWith(c.config.signatureAlgorithm(), data)
+}
+
+// signWith generates a signature for the given data using the client's private key and the provided algorithm.
+func (c client) signWith(algorithm SignatureAlgorithm, data []byte) (string, error) {
+	var hasher hash.Hash
+
+	switch algorithm {
+	case SignatureAlgorithmSHA1:
+		hasher = sha1.New()
+	case SignatureAlgorithmSHA3256:
+		hasher = sha3.New256()
+	default:
+		return "", fmt.Errorf("liqpay client: unsupported signature algorithm %q", algorithm)
+	}
+
 	hasher.Write([]byte(c.config.PrivateKey))
 	hasher.Write(data)
 	hasher.Write([]byte(c.config.PrivateKey))
-	return base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
 }
 
 // encode encodes the payload to base64 format.
@@ -76,8 +155,11 @@ func (c client) injectMissingKeys(payload any) (map[string]interface{}, error) {
 		return nil, err
 	}
 
+	// Numbers are decoded as json.Number, so amounts are encoded back exactly as they were.
 	var data map[string]interface{}
-	if err := json.Unmarshal(payloadBytes, &data); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(payloadBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
 		return nil, err
 	}
 
@@ -92,30 +174,68 @@ func (c client) injectMissingKeys(payload any) (map[string]interface{}, error) {
 	return data, nil
 }
 
-// sendClientRequest sends a client-server request to LiqPay API.
-func (c client) sendClientRequest(payload any) (*http.Response, error) {
+// signPayload injects missing keys into the payload, encodes and signs it.
+// It returns the data and signature form values expected by LiqPay API and the injected payload.
+func (c client) signPayload(payload any) (url.Values, map[string]interface{}, error) {
 	injectedPayload, err := c.injectMissingKeys(payload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to inject missing keys: %w", err)
+		return nil, nil, fmt.Errorf("liqpay client: failed to inject missing keys: %w", err)
 	}
 
 	encodedJSON, err := c.encode(injectedPayload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to encode payload: %w", err)
+		return nil, nil, fmt.Errorf("liqpay client: failed to encode payload: %w", err)
+	}
+	signature, err := c.sign([]byte(encodedJSON))
+	if err != nil {
+		return nil, nil, err
 	}
-	signature := c.sign([]byte(encodedJSON))
 
-	formData := url.Values{
+	return url.Values{
 		"data":      {encodedJSON},
 		"signature": {signature},
+	}, injectedPayload, nil
+}
+
+// sendClientRequest sends a client-server request to LiqPay API.
+func (c client) sendClientRequest(ctx context.Context, payload any) (*http.Response, error) {
+	formData, injectedPayload, err := c.signPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := bytes.NewBufferString(formData.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.clientServerURL(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to create new http request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	logger := c.config.logger().With(
+		slog.Any("action", injectedPayload["action"]),
+		slog.Any("order_id", injectedPayload["order_id"]),
+	)
+	logger.DebugContext(ctx, "liqpay client request", slog.String("url", req.URL.String()))
 
-	resp, err := c.httpClient.PostForm(ClientServerURL, formData)
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		logger.WarnContext(ctx, "liqpay client request failed",
+			slog.Duration("latency", time.Since(start)),
+			slog.String("error", err.Error()),
+		)
 		return nil, fmt.Errorf("liqpay client: failed to parse liqpay form: %w", err)
 	}
 	defer resp.Body.Close()
 
+	logger.DebugContext(ctx, "liqpay client response",
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("http_status", resp.StatusCode),
+	)
+
 	return resp, nil
 }
 
@@ -135,91 +255,161 @@ func (c client) getClientRedirectURL(resp *http.Response) (string, error) {
 	return "", fmt.Errorf("redirect not found")
 }
 
-// prepareServerRequest prepares a server-server HTTP request to LiqPay API.
-func (c client) prepareServerRequest(payload any) (*http.Request, error) {
-	injectedPayload, err := c.injectMissingKeys(payload)
-	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to inject missing keys: %w", err)
-	}
+// serverRequest is a prepared server-server request to LiqPay API.
+type serverRequest struct {
+	*http.Request
+	action  Action
+	orderID string
+}
 
-	encodedJSON, err := c.encode(injectedPayload)
+// prepareServerRequest prepares a server-server HTTP request to LiqPay API.
+func (c client) prepareServerRequest(ctx context.Context, payload any) (*serverRequest, error) {
+	formData, injectedPayload, err := c.signPayload(payload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to encode payload: %w", err)
-	}
-	signature := c.sign([]byte(encodedJSON))
-
-	formData := url.Values{
-		"data":      {encodedJSON},
-		"signature": {signature},
+		return nil, err
 	}
 
 	reqBody := bytes.NewBufferString(formData.Encode())
-	req, err := http.NewRequest(http.MethodPost, ServerServerURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.serverServerURL(), reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("liqpay client: failed to create new http request: %w", err)
 	}
 
-	return req, nil
+	action, _ := injectedPayload["action"].(string)
+	orderID, _ := injectedPayload["order_id"].(string)
+
+	return &serverRequest{Request: req, action: Action(action), orderID: orderID}, nil
 }
 
-// sendServerRequest sends a server-server request to LiqPay API.
-func (c client) sendServerRequest(req *http.Request, v any) error {
-	if c.config.Debug {
-		log.Printf("[LIQPAY DEBUG] Request method: %s, url: %s\n", req.Method, req.URL.String())
+// sendServerRequest sends a server-server request to LiqPay API, retrying it according to the client's retry policy.
+func (c client) sendServerRequest(req *serverRequest, v any) error {
+	var (
+		ctx    = req.Context()
+		policy = c.config.RetryPolicy
+		logger = c.config.logger().With(
+			slog.String("action", string(req.action)),
+			slog.String("order_id", req.orderID),
+		)
+	)
+
+	for attempt := 1; ; attempt++ {
+		// Retries are decoded into a fresh value, so the response never mixes fields of different attempts.
+		target := v
+		if attempt > 1 {
+			target = newResponse(v)
+		}
+
+		err := c.sendServerRequestOnce(req.Request, logger, target)
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || !policy.retryOn(req.action, err) {
+			if attempt > 1 {
+				setResponse(v, target)
+			}
+			return err
+		}
+
+		logger.InfoContext(ctx, "liqpay server request retry",
+			slog.Int("attempt", attempt),
+			slog.String("error", err.Error()),
+		)
+
+		if err := policy.wait(ctx, attempt); err != nil {
+			return err
+		}
+
+		retry, err := retryRequest(req.Request)
+		if err != nil {
+			return err
+		}
+		req = &serverRequest{Request: retry, action: req.action, orderID: req.orderID}
 	}
+}
+
+// sendServerRequestOnce sends a single server-server request attempt to LiqPay API.
+func (c client) sendServerRequestOnce(req *http.Request, logger *slog.Logger, v any) error {
+	ctx := req.Context()
+	logger.DebugContext(ctx, "liqpay server request", slog.String("url", req.URL.String()))
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		logger.WarnContext(ctx, "liqpay server request failed",
+			slog.Duration("latency", time.Since(start)),
+			slog.String("error", err.Error()),
+		)
 		return fmt.Errorf("liqpay client: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var res map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return fmt.Errorf("liqpay client: failed to decode json: %w", err)
-	}
-
-	if v == nil {
-		return nil
-	}
+	logger = logger.With(
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("http_status", resp.StatusCode),
+	)
 
-	jsonResp, err := json.Marshal(res)
+	body, err := readResponse(resp)
 	if err != nil {
-		return fmt.Errorf("liqpay client: failed to marshal response: %w", err)
-	}
-
-	if c.config.Debug {
-		log.Printf("[LIQPAY DEBUG] Response: %s", string(jsonResp))
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		logger.WarnContext(ctx, "liqpay server response read failed", slog.String("error", err.Error()))
+		return &ResponseError{StatusCode: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Body: body, Err: err}
 	}
 
-	if err := json.Unmarshal(jsonResp, v); err != nil {
-		return fmt.Errorf("liqpay client: failed to unmarshal response: %w", err)
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		logger.WarnContext(ctx, "liqpay server request failed")
+		return &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
 	}
 
-	if res["status"] == "error" || res["status"] == "failure" || res["result"] == "error" {
-		errResp := &APIError{
-			Status: res["status"].(string),
-			Code:   res["err_code"].(string),
-			Desc:   res["err_description"].(string),
-		}
-
-		if c.config.Debug {
-			log.Printf("[LIQPAY DEBUG] Error status: %s, status_code: %d, code: %s, description: %s\n",
-				errResp.Status, resp.StatusCode, errResp.Code, errResp.Desc)
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		var res map[string]any
+		if json.Unmarshal(body, &res) == nil {
+			logger.DebugContext(ctx, "liqpay server response", slog.Any("response", redact(res)))
 		}
-
-		return errResp
 	}
 
-	return nil
+	err = decodeServerResponse(resp, body, v)
+
+	var apiErr *APIError
+	var respErr *ResponseError
+	switch {
+	case errors.As(err, &apiErr):
+		logger.WarnContext(ctx, "liqpay api error",
+			slog.String("status", apiErr.Status),
+			slog.String("err_code", apiErr.Code),
+			slog.String("err_description", apiErr.Desc),
+		)
+	case errors.As(err, &respErr):
+		logger.WarnContext(ctx, "liqpay server response invalid",
+			slog.String("content_type", respErr.ContentType),
+			slog.String("error", respErr.Err.Error()),
+		)
+	}
+
+	return err
 }
 
 // CreateCheckout creates a new checkout link.
 func (c client) CreateCheckout(data *CheckoutRequest) (string, error) {
+	return c.CreateCheckoutContext(context.Background(), data)
+}
+
+// CreateCheckoutContext creates a new checkout link using the provided context.
+func (c client) CreateCheckoutContext(ctx context.Context, data *CheckoutRequest) (string, error) {
 	data.Action = ActionPay
 
-	resp, err := c.sendClientRequest(data)
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return "", err
+	}
+
+	if err := validateSplitRules(data.Amount, data.SplitRules); err != nil {
+		return "", err
+	}
+
+	resp, err := c.sendClientRequest(ctx, data)
 	if err != nil {
 		return "", err
 	}
@@ -234,10 +424,19 @@ func (c client) CreateCheckout(data *CheckoutRequest) (string, error) {
 
 // CreateSubscription creates a new subscription link.
 func (c client) CreateSubscription(data *SubscriptionRequest) (string, error) {
+	return c.CreateSubscriptionContext(context.Background(), data)
+}
+
+// CreateSubscriptionContext creates a new subscription link using the provided context.
+func (c client) CreateSubscriptionContext(ctx context.Context, data *SubscriptionRequest) (string, error) {
 	data.Action = ActionSubscribe
 	data.Subscribe = "1"
 
-	resp, err := c.sendClientRequest(data)
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return "", err
+	}
+
+	resp, err := c.sendClientRequest(ctx, data)
 	if err != nil {
 		return "", err
 	}
@@ -252,9 +451,18 @@ func (c client) CreateSubscription(data *SubscriptionRequest) (string, error) {
 
 // UpdateSubscription updates an existing subscription.
 func (c client) UpdateSubscription(data *EditSubscriptionRequest) (*SubscriptionResponse, error) {
+	return c.UpdateSubscriptionContext(context.Background(), data)
+}
+
+// UpdateSubscriptionContext updates an existing subscription using the provided context.
+func (c client) UpdateSubscriptionContext(ctx context.Context, data *EditSubscriptionRequest) (*SubscriptionResponse, error) {
 	data.Action = ActionSubscribeUpdate
 
-	req, err := c.prepareServerRequest(data)
+	if err := data.Amount.Validate(Currency(data.Currency)); err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -273,9 +481,14 @@ func (c client) UpdateSubscription(data *EditSubscriptionRequest) (*Subscription
 
 // RemoveSubscription removes a subscription.
 func (c client) RemoveSubscription(orderID string) (*SubscriptionResponse, error) {
+	return c.RemoveSubscriptionContext(context.Background(), orderID)
+}
+
+// RemoveSubscriptionContext removes a subscription using the provided context.
+func (c client) RemoveSubscriptionContext(ctx context.Context, orderID string) (*SubscriptionResponse, error) {
 	data := &UnsubscribeRequest{Action: ActionUnsubscribe, OrderID: orderID}
 
-	req, err := c.prepareServerRequest(data)
+	req, err := c.prepareServerRequest(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -294,9 +507,18 @@ func (c client) RemoveSubscription(orderID string) (*SubscriptionResponse, error
 
 // CreateInvoice creates a new invoice.
 func (c client) CreateInvoice(data *InvoiceRequest) (*InvoiceResponse, error) {
+	return c.CreateInvoiceContext(context.Background(), data)
+}
+
+// CreateInvoiceContext creates a new invoice using the provided context.
+func (c client) CreateInvoiceContext(ctx context.Context, data *InvoiceRequest) (*InvoiceResponse, error) {
 	data.Action = ActionInvoiceSend
 
-	req, err := c.prepareServerRequest(data)
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -315,9 +537,14 @@ func (c client) CreateInvoice(data *InvoiceRequest) (*InvoiceResponse, error) {
 
 // CancelInvoice cancels an invoice.
 func (c client) CancelInvoice(orderID string) (*CancelInvoiceResponse, error) {
+	return c.CancelInvoiceContext(context.Background(), orderID)
+}
+
+// CancelInvoiceContext cancels an invoice using the provided context.
+func (c client) CancelInvoiceContext(ctx context.Context, orderID string) (*CancelInvoiceResponse, error) {
 	data := &CancelInvoiceRequest{Action: ActionInvoiceCancel, OrderID: orderID}
 
-	req, err := c.prepareServerRequest(data)
+	req, err := c.prepareServerRequest(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -336,9 +563,14 @@ func (c client) CancelInvoice(orderID string) (*CancelInvoiceResponse, error) {
 
 // Status retrieves the status of an order.
 func (c client) Status(orderID string) (*StatusResponse, error) {
+	return c.StatusContext(context.Background(), orderID)
+}
+
+// StatusContext retrieves the status of an order using the provided context.
+func (c client) StatusContext(ctx context.Context, orderID string) (*StatusResponse, error) {
 	data := &StatusRequest{Action: ActionStatus, OrderID: orderID}
 
-	req, err := c.prepareServerRequest(data)
+	req, err := c.prepareServerRequest(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -356,10 +588,15 @@ func (c client) Status(orderID string) (*StatusResponse, error) {
 }
 
 // Refund processes a refund for an order.
-func (c client) Refund(orderID string, amount string) (*RefundResponse, error) {
+func (c client) Refund(orderID string, amount Amount) (*RefundResponse, error) {
+	return c.RefundContext(context.Background(), orderID, amount)
+}
+
+// RefundContext processes a refund for an order using the provided context.
+func (c client) RefundContext(ctx context.Context, orderID string, amount Amount) (*RefundResponse, error) {
 	data := &RefundRequest{Action: ActionRefund, OrderID: orderID, Amount: amount}
 
-	req, err := c.prepareServerRequest(data)
+	req, err := c.prepareServerRequest(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -377,21 +614,23 @@ func (c client) Refund(orderID string, amount string) (*RefundResponse, error) {
 }
 
 // ValidateCallback validates the callback data and signature received from LiqPay.
+// The signature is computed over the base64-encoded data exactly as it was received.
+// Any of the configured callback signature algorithms is accepted.
 func (c client) ValidateCallback(data string, signature string) error {
-	decodedData, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
+	if _, err := base64.StdEncoding.DecodeString(data); err != nil {
 		return fmt.Errorf("liqpay client: failed to decode data: %w", err)
 	}
 
-	expectedSignature := c.sign(decodedData)
-	decodedSignature, err := base64.StdEncoding.DecodeString(signature)
-	if err != nil {
-		return fmt.Errorf("liqpay client: failed to decode signature: %w", err)
-	}
+	for _, algorithm := range c.config.callbackSignatureAlgorithms() {
+		expectedSignature, err := c.signWith(algorithm, []byte(data))
+		if err != nil {
+			return err
+		}
 
-	if !bytes.Equal(decodedSignature, []byte(expectedSignature)) {
-		return errors.New("liqpay client: callback signature verification failed")
+		if subtle.ConstantTimeCompare([]byte(signature), []byte(expectedSignature)) == 1 {
+			return nil
+		}
 	}
 
-	return nil
+	return ErrInvalidCallbackSignature
 }
@@ -1,16 +1,44 @@
 package liqpay
 
+import "log/slog"
+
 const (
 	ServerServerURL   = "https://www.liqpay.ua/api/request"
 	ClientServerURL   = "https://www.liqpay.ua/api/3/checkout"
 	CurrentAPIVersion = "3"
 )
 
+type SignatureAlgorithm string
+
+const (
+	SignatureAlgorithmSHA1    SignatureAlgorithm = "sha1"     // Default signature algorithm
+	SignatureAlgorithmSHA3256 SignatureAlgorithm = "sha3-256" // SHA3-256 signature algorithm
+)
+
 // Config represents the configuration parameters required for interacting with the LiqPay API.
 type Config struct {
 	PrivateKey string // PrivateKey is the private key used for API authentication.
 	PublicKey  string // PublicKey is the public key used for API authentication.
-	Debug      bool   // Debug specifies whether debug mode is enabled.
+	Debug      bool   // Debug enables debug logging to stderr when Logger is not set.
+
+	// Logger receives structured logs of requests and responses. Sensitive fields such as card
+	// details, card tokens and phone numbers are redacted. Defaults to no logging unless Debug is set.
+	Logger *slog.Logger
+
+	// SignatureAlgorithm is the algorithm used to sign requests and validate callbacks.
+	// Defaults to SignatureAlgorithmSHA1.
+	SignatureAlgorithm SignatureAlgorithm
+	// CallbackSignatureAlgorithms are the algorithms accepted when validating callbacks.
+	// Set it to both algorithms during a migration window. Defaults to SignatureAlgorithm.
+	CallbackSignatureAlgorithms []SignatureAlgorithm
+
+	// ServerServerURL is the endpoint for server-server requests. Defaults to the ServerServerURL constant.
+	ServerServerURL string
+	// ClientServerURL is the checkout endpoint for client-server requests. Defaults to the ClientServerURL constant.
+	ClientServerURL string
+
+	// RetryPolicy controls retries of failed server-server requests. Nil disables retries.
+	RetryPolicy *RetryPolicy
 }
 
 // NewConfig creates a new Config instance with the provided public key, private key, and debug mode settings.
@@ -21,3 +49,47 @@ func NewConfig(publicKey, privateKey string, debugMode bool) *Config {
 		Debug:      debugMode,
 	}
 }
+
+// signatureAlgorithm returns the configured signature algorithm or the default one.
+func (c *Config) signatureAlgorithm() SignatureAlgorithm {
+	if c.SignatureAlgorithm == "" {
+		return SignatureAlgorithmSHA1
+	}
+	return c.SignatureAlgorithm
+}
+
+// callbackSignatureAlgorithms returns the algorithms accepted when validating callbacks.
+func (c *Config) callbackSignatureAlgorithms() []SignatureAlgorithm {
+	if len(c.CallbackSignatureAlgorithms) == 0 {
+		return []SignatureAlgorithm{c.signatureAlgorithm()}
+	}
+	return c.CallbackSignatureAlgorithms
+}
+
+// serverServerURL returns the configured server-server endpoint or the default one.
+func (c *Config) serverServerURL() string {
+	if c.ServerServerURL == "" {
+		return ServerServerURL
+	}
+	return c.ServerServerURL
+}
+
+// clientServerURL returns the configured client-server endpoint or the default one.
+func (c *Config) clientServerURL() string {
+	if c.ClientServerURL == "" {
+		return ClientServerURL
+	}
+	return c.ClientServerURL
+}
+
+// logger returns the configured logger, the debug logger or a logger that discards everything.
+func (c *Config) logger() *slog.Logger {
+	switch {
+	case c.Logger != nil:
+		return c.Logger
+	case c.Debug:
+		return debugLogger
+	default:
+		return discardLogger
+	}
+}
@@ -0,0 +1,87 @@
+package liqpay
+
+import "fmt"
+
+// Money is an amount in a currency.
+type Money struct {
+	Amount   Amount
+	Currency Currency
+}
+
+// String returns the amount with the minor units of the currency followed by the currency, e.g. "7.30 USD".
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Amount.StringFixed(m.Currency.MinorUnits()), m.Currency)
+}
+
+// DCCOffer is a dynamic currency conversion offer: the payment amount in the currency of the customer's card.
+type DCCOffer struct {
+	Payment   Money  // Payment amount in the payment currency
+	Converted Money  // Amount the customer pays in the card currency if the offer is accepted
+	Rate      Amount // Units of the card currency per unit of the payment currency
+}
+
+// String returns the offer as it is usually shown to the customer, e.g. "100.00 UAH = 2.45 USD (1 UAH = 0.0245 USD)".
+func (o DCCOffer) String() string {
+	return fmt.Sprintf("%s = %s (1 %s = %s %s)", o.Payment, o.Converted, o.Payment.Currency, o.Rate, o.Converted.Currency)
+}
+
+// newDCCOffer returns the conversion offer of a payment in the dcc_verify status.
+// If the response has no converted amount, it is calculated at the offered rate.
+func newDCCOffer(v *CardPaymentResponse) *DCCOffer {
+	offer := &DCCOffer{
+		Payment:   Money{Amount: v.Amount, Currency: v.Currency},
+		Converted: Money{Amount: v.DCCAmount, Currency: v.DCCCurrency},
+		Rate:      v.DCCRate,
+	}
+	if offer.Converted.Amount.IsZero() && offer.Converted.Currency != "" {
+		offer.Converted.Amount = v.Amount.Convert(v.DCCRate, v.DCCCurrency)
+	}
+	return offer
+}
+
+// Conversion is a payment as debited from the sender and credited to the receiver.
+// The currencies differ if the payment was converted, e.g. with DCC.
+type Conversion struct {
+	Debit  Money // Amount debited from the sender in the currency of the card
+	Credit Money // Amount credited to the receiver
+}
+
+// Converted reports whether the debit and credit currencies differ.
+func (c Conversion) Converted() bool {
+	return c.Debit.Currency != "" && c.Credit.Currency != "" && c.Debit.Currency != c.Credit.Currency
+}
+
+// String returns both sides of the payment, e.g. "2.45 USD -> 100.00 UAH", or a single amount if it was not converted.
+func (c Conversion) String() string {
+	if !c.Converted() {
+		return c.Credit.String()
+	}
+	return fmt.Sprintf("%s -> %s", c.Debit, c.Credit)
+}
+
+func newConversion(amountDebit Amount, currencyDebit string, amountCredit Amount, currencyCredit string) Conversion {
+	return Conversion{
+		Debit:  Money{Amount: amountDebit, Currency: Currency(currencyDebit)},
+		Credit: Money{Amount: amountCredit, Currency: Currency(currencyCredit)},
+	}
+}
+
+// Conversion returns the debit and credit sides of the payment.
+func (r *StatusResponse) Conversion() Conversion {
+	return newConversion(r.AmountDebit, r.CurrencyDebit, r.AmountCredit, r.CurrencyCredit)
+}
+
+// Conversion returns the debit and credit sides of the payment.
+func (r *CardPaymentResponse) Conversion() Conversion {
+	return newConversion(r.AmountDebit, r.CurrencyDebit, r.AmountCredit, r.CurrencyCredit)
+}
+
+// Conversion returns the debit and credit sides of the payment.
+func (r *ReportPayment) Conversion() Conversion {
+	return newConversion(r.AmountDebit, r.CurrencyDebit, r.AmountCredit, r.CurrencyCredit)
+}
+
+// Conversion returns the debit and credit sides of the payment.
+func (c *Callback) Conversion() Conversion {
+	return newConversion(c.AmountDebit, c.CurrencyDebit, c.AmountCredit, c.CurrencyCredit)
+}
@@ -0,0 +1,182 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNewDCCOffer(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "converted amount",
+			body: `{"status":"dcc_verify","amount":100,"currency":"UAH","dcc_amount":2.46,"dcc_currency":"USD","dcc_rate":0.0245}`,
+			want: "100.00 UAH = 2.46 USD (1 UAH = 0.0245 USD)",
+		},
+		{
+			name: "amount calculated at the rate",
+			body: `{"status":"dcc_verify","amount":100,"currency":"UAH","dcc_currency":"USD","dcc_rate":0.0245}`,
+			want: "100.00 UAH = 2.45 USD (1 UAH = 0.0245 USD)",
+		},
+		{
+			name: "rounded to minor units of the card currency",
+			body: `{"status":"dcc_verify","amount":"7.35","currency":"USD","dcc_currency":"UAH","dcc_rate":"41.1234"}`,
+			want: "7.35 USD = 302.26 UAH (1 USD = 41.1234 UAH)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v CardPaymentResponse
+			if err := json.Unmarshal([]byte(tt.body), &v); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			step := newPaymentStepResult(&v)
+			if step.Step != PaymentStepDCC || step.DCC == nil {
+				t.Fatalf("newPaymentStepResult() = %s step with offer %v, want %s step with an offer", step.Step, step.DCC, PaymentStepDCC)
+			}
+			if got := step.DCC.String(); got != tt.want {
+				t.Errorf("DCC offer = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversion(t *testing.T) {
+	const (
+		converted   = `{"amount_debit":2.46,"currency_debit":"USD","amount_credit":100,"currency_credit":"UAH"}`
+		unconverted = `{"amount_debit":100,"currency_debit":"UAH","amount_credit":100,"currency_credit":"UAH"}`
+	)
+
+	tests := []struct {
+		name          string
+		body          string
+		conversion    func(body []byte) (Conversion, error)
+		want          string
+		wantConverted bool
+	}{
+		{
+			name: "status response",
+			body: converted,
+			conversion: func(body []byte) (Conversion, error) {
+				var v StatusResponse
+				err := json.Unmarshal(body, &v)
+				return v.Conversion(), err
+			},
+			want:          "2.46 USD -> 100.00 UAH",
+			wantConverted: true,
+		},
+		{
+			name: "card payment response",
+			body: converted,
+			conversion: func(body []byte) (Conversion, error) {
+				var v CardPaymentResponse
+				err := json.Unmarshal(body, &v)
+				return v.Conversion(), err
+			},
+			want:          "2.46 USD -> 100.00 UAH",
+			wantConverted: true,
+		},
+		{
+			name: "token payment response",
+			body: converted,
+			conversion: func(body []byte) (Conversion, error) {
+				var v TokenPaymentResponse
+				err := json.Unmarshal(body, &v)
+				return v.Conversion(), err
+			},
+			want:          "2.46 USD -> 100.00 UAH",
+			wantConverted: true,
+		},
+		{
+			name: "report payment",
+			body: converted,
+			conversion: func(body []byte) (Conversion, error) {
+				var v ReportPayment
+				err := json.Unmarshal(body, &v)
+				return v.Conversion(), err
+			},
+			want:          "2.46 USD -> 100.00 UAH",
+			wantConverted: true,
+		},
+		{
+			name: "callback not converted",
+			body: unconverted,
+			conversion: func(body []byte) (Conversion, error) {
+				var v Callback
+				err := json.Unmarshal(body, &v)
+				return v.Conversion(), err
+			},
+			want: "100.00 UAH",
+		},
+		{
+			name: "no debit side",
+			body: `{"amount_credit":100,"currency_credit":"UAH"}`,
+			conversion: func(body []byte) (Conversion, error) {
+				var v StatusResponse
+				err := json.Unmarshal(body, &v)
+				return v.Conversion(), err
+			},
+			want: "100.00 UAH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.conversion([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Conversion() = %s, want %s", got, tt.want)
+			}
+			if got.Converted() != tt.wantConverted {
+				t.Errorf("Converted() = %t, want %t", got.Converted(), tt.wantConverted)
+			}
+		})
+	}
+}
+
+func TestConfirmDCC(t *testing.T) {
+	tests := []struct {
+		accept  bool
+		wantDCC string
+	}{
+		{accept: true, wantDCC: "Y"},
+		{accept: false, wantDCC: "N"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantDCC, func(t *testing.T) {
+			transport := &scriptedTransport{replies: []func() (*http.Response, error){
+				reply(http.StatusOK, `{"status":"success","order_id":"order-1","amount_debit":2.46,"currency_debit":"USD","amount_credit":100,"currency_credit":"UAH"}`),
+			}}
+			c := NewClient(NewConfig("public", "private", false), &http.Client{Transport: transport})
+
+			step, err := c.ConfirmDCC("confirm-1", tt.accept)
+			if err != nil {
+				t.Fatalf("ConfirmDCC() error = %v", err)
+			}
+			if step.Step != PaymentStepDone {
+				t.Errorf("ConfirmDCC() step = %s, want %s", step.Step, PaymentStepDone)
+			}
+
+			data := requestData(t, transport.bodies[0])
+			want := map[string]any{"action": string(ActionConfirm), "token": "confirm-1", "dcc": tt.wantDCC}
+			for key, want := range want {
+				if got := data[key]; got != want {
+					t.Errorf("data[%s] = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := NewClient(NewConfig("public", "private", false), nil).ConfirmDCC("", true); err == nil {
+		t.Error("ConfirmDCC() without a token error = nil, want error")
+	}
+}
@@ -1,6 +1,12 @@
 package liqpay
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type AntiFraudError string
 
@@ -100,21 +106,41 @@ const (
 	FinancialTransactionLimitExceeded      FinancialError = 104  // Transaction limit for the token exceeded
 	FinancialUnsupportedCard               FinancialError = 105  // Card is not supported
 	FinancialPreauthorizationNotAllowed    FinancialError = 106  // Merchant not allowed to preauthorize
-	FinancialAcquirerDoesNotSupport3DS     FinancialError = 107  // Such token does not exist
+	FinancialAcquirerDoesNotSupport3DS     FinancialError = 107  // Acquirer does not support 3DS
 	FinancialTokenNotFound                 FinancialError = 108  // Such token does not exist
-	FinancialTokenDoesNotExist             FinancialError = 109  // IP attempt limit exceeded
-	FinancialIPAttemptsLimitExceeded       FinancialError = 110  // Session expired
-	FinancialSessionExpired                FinancialError = 111  // Card branch blocked
-	FinancialCardBranchBlocked             FinancialError = 112  // Daily card branch limit reached
-	FinancialDailyCardBranchLimitExceeded  FinancialError = 113  // Temporary restriction on P2P payments from PB cards to cards of foreign banks
-	FinancialP2PBlocked                    FinancialError = 113  // Daily limit for using the card reached
-	FinancialDailyTransactionLimitExceeded FinancialError = 2903 // Such order_id already exists
-	FinancialDuplicateOrderID              FinancialError = 2915 // Payments to this country are forbidden
-	FinancialPaymentCountryForbidden       FinancialError = 3914 // Card expiration date expired
-	FinancialCardExpirationExpired         FinancialError = 9851 // Incorrect card number
-	FinancialInvalidCardNumber             FinancialError = 9852 // Payment declined. Try again later
-	FinancialPaymentDeclined               FinancialError = 9854 // Card does not support this type of transaction
+	FinancialIPAttemptsLimitExceeded       FinancialError = 109  // IP attempt limit exceeded
+	FinancialSessionExpired                FinancialError = 110  // Session expired
+	FinancialCardBranchBlocked             FinancialError = 111  // Card branch blocked
+	FinancialDailyCardBranchLimitExceeded  FinancialError = 112  // Daily card branch limit reached
+	FinancialP2PBlocked                    FinancialError = 113  // Temporary restriction on P2P payments from PB cards to cards of foreign banks
+	FinancialDailyTransactionLimitExceeded FinancialError = 2903 // Daily limit for using the card reached
+	FinancialDuplicateOrderID              FinancialError = 2915 // Such order_id already exists
+	FinancialPaymentCountryForbidden       FinancialError = 3914 // Payments to this country are forbidden
+	FinancialCardExpirationExpired         FinancialError = 9851 // Card expiration date expired
+	FinancialInvalidCardNumber             FinancialError = 9852 // Incorrect card number
+	FinancialPaymentDeclined               FinancialError = 9854 // Payment declined. Try again later
 	FinancialUnsupportedTransactionType    FinancialError = 9855 // Card does not support this type of transaction
+
+	// Deprecated: use FinancialTokenNotFound. The constant had the value of FinancialIPAttemptsLimitExceeded by mistake.
+	FinancialTokenDoesNotExist = FinancialTokenNotFound
+)
+
+// ErrorCategory is the category of an APIError code.
+type ErrorCategory string
+
+const (
+	ErrorCategoryUnknown      ErrorCategory = "unknown"       // Code is empty or not recognized
+	ErrorCategoryAntiFraud    ErrorCategory = "anti_fraud"    // Payment declined by the Bank's anti-fraud system, see AntiFraudError
+	ErrorCategoryNonFinancial ErrorCategory = "non_financial" // Request or payment flow error, see NonFinancialError
+	ErrorCategoryFinancial    ErrorCategory = "financial"     // Payment declined by the processing, see FinancialError
+)
+
+// Sentinel errors matched by APIError with errors.Is.
+var (
+	ErrPaymentNotFound  = errors.New("liqpay: payment not found")
+	ErrDuplicateOrderID = errors.New("liqpay: order_id already exists")
+	ErrInvalidSignature = errors.New("liqpay: invalid signature")
+	ErrQRCodeExpired    = errors.New("liqpay: qr code expired")
 )
 
 // APIError represents an error returned by the LiqPay API.
@@ -122,16 +148,195 @@ type APIError struct {
 	Status string `json:"status"`
 	Code   string `json:"err_code"`
 	Desc   string `json:"err_description"`
+	Erc    string `json:"err_erc,omitempty"`
+	Body   string `json:"-"` // Raw response body, empty for errors detected by the client
 }
 
 func (e APIError) Error() string {
 	return fmt.Sprintf("status: %s, code: %s, description: %s", e.Status, e.Code, e.Desc)
 }
 
+// Is reports whether the error matches one of the sentinel errors:
+// ErrPaymentNotFound, ErrDuplicateOrderID, ErrInvalidSignature or ErrQRCodeExpired.
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrPaymentNotFound:
+		return e.NonFinancial() == NonFinancialPaymentNotFound
+	case ErrDuplicateOrderID:
+		return e.NonFinancial() == NonFinancialDuplicateOrderID || e.Financial() == FinancialDuplicateOrderID
+	case ErrInvalidSignature:
+		switch e.NonFinancial() {
+		case NonFinancialInvalidSignature, NonFinancialInvalidRequestSignature:
+			return true
+		}
+	case ErrQRCodeExpired:
+		return e.NonFinancial() == NonFinancialQRCodeExpired
+	}
+	return false
+}
+
+// Category returns the category of the error code.
+// The error is financial if it carries a numeric err_erc or err_code.
+func (e APIError) Category() ErrorCategory {
+	switch {
+	case e.Code == "" && e.Erc == "":
+		return ErrorCategoryUnknown
+	case e.AntiFraud() != "":
+		return ErrorCategoryAntiFraud
+	case e.Financial() != 0:
+		return ErrorCategoryFinancial
+	case e.NonFinancial() != "":
+		return ErrorCategoryNonFinancial
+	}
+	return ErrorCategoryUnknown
+}
+
+// AntiFraud returns the anti-fraud error code, or an empty string if the error is not an anti-fraud decline.
+func (e APIError) AntiFraud() AntiFraudError {
+	switch code := AntiFraudError(e.Code); code {
+	case AntiFraudLimitExceeded, AntiFraudFraudDetected, AntiFraudDeclinedTransaction:
+		return code
+	}
+	return ""
+}
+
+// NonFinancial returns the non-financial error code, or an empty string if the error code is financial or anti-fraud.
+func (e APIError) NonFinancial() NonFinancialError {
+	code := NonFinancialError(e.Code)
+	if code == NonFinancialCardNot3DSupported {
+		return code
+	}
+	if _, err := strconv.Atoi(e.Code); err == nil || e.AntiFraud() != "" {
+		return ""
+	}
+	return code
+}
+
+// Financial returns the financial error code parsed from err_erc, or from a numeric err_code.
+// It returns 0 if the error carries no financial code.
+func (e APIError) Financial() FinancialError {
+	if n, err := strconv.Atoi(e.Erc); err == nil {
+		return FinancialError(n)
+	}
+	if NonFinancialError(e.Code) == NonFinancialCardNot3DSupported {
+		return 0
+	}
+	if n, err := strconv.Atoi(e.Code); err == nil {
+		return FinancialError(n)
+	}
+	return 0
+}
+
+// Retryable reports whether the same request may succeed if it is retried later:
+// the payment is still being processed or LiqPay awaits additional information.
+// Financial errors are final declines of the payment and are never retryable.
+func (e APIError) Retryable() bool {
+	switch e.NonFinancial() {
+	case NonFinancialPaymentProcessing, NonFinancialAdditionalInfoRequired:
+		return true
+	}
+	return false
+}
+
+// CustomerFacing reports whether the error is caused by the customer's card, limits or actions,
+// so its description can be shown to the customer, who may fix it by using another card or trying again.
+// Other errors are caused by the merchant integration or configuration and should not be shown to the customer.
+func (e APIError) CustomerFacing() bool {
+	switch e.Category() {
+	case ErrorCategoryAntiFraud:
+		return true
+	case ErrorCategoryFinancial:
+		switch e.Financial() {
+		case FinancialGeneralError,
+			FinancialInvalidTokenMerchant,
+			FinancialPreauthorizationNotAllowed,
+			FinancialAcquirerDoesNotSupport3DS,
+			FinancialDuplicateOrderID:
+			return false
+		}
+		return true
+	case ErrorCategoryNonFinancial:
+		switch code := e.NonFinancial(); code {
+		case NonFinancialSMSSendFailed,
+			NonFinancialSMSOTPIncorrect,
+			NonFinancialCardLiqpayDefault,
+			NonFinancialInvalidCardType,
+			NonFinancialInvalidCardCountry,
+			NonFinancialAmountBelowLimit,
+			NonFinancialPaymentAmountLimit,
+			NonFinancialAmountLimitExceeded,
+			NonFinancialPaymentSenderCard,
+			NonFinancialVerifyCodeRequired,
+			NonFinancialIncorrectVerifyCode,
+			NonFinancialPaymentCurrencyError,
+			NonFinancialPhoneParameterEmpty,
+			NonFinancialInvalidPhoneNumber,
+			NonFinancialInvalidCardNumber,
+			NonFinancialCardBINNotFound,
+			NonFinancialMPIVerificationFailed,
+			NonFinancialCardNot3DSupported:
+			return true
+		default:
+			return strings.HasPrefix(string(code), "expired_")
+		}
+	}
+	return false
+}
+
+// HTTPError represents an unexpected HTTP response from LiqPay API.
+type HTTPError struct {
+	StatusCode int    // HTTP status code
+	Status     string // HTTP status line, e.g. "502 Bad Gateway"
+	Body       []byte // Raw response body, truncated to 32 MiB
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("liqpay client: unexpected http status: %s", e.Status)
+}
+
+// TokenError represents a failed card token payment with a token-related financial error code (101-109).
+type TokenError struct {
+	*APIError
+}
+
+func (e *TokenError) Unwrap() error {
+	return e.APIError
+}
+
+// RequiresNewCard reports whether the card token can no longer be charged and the customer has to provide a new card.
+func (e *TokenError) RequiresNewCard() bool {
+	switch e.Financial() {
+	case FinancialInvalidTokenMerchant,
+		FinancialInactiveToken,
+		FinancialMaxPurchaseAmountExceeded,
+		FinancialTransactionLimitExceeded,
+		FinancialUnsupportedCard,
+		FinancialTokenNotFound:
+		return true
+	}
+	return false
+}
+
+// Retryable reports whether the payment may succeed if it is retried later with the same card token:
+// the IP attempts limit resets over time, while other token errors do not go away.
+func (e *TokenError) Retryable() bool {
+	return e.Financial() == FinancialIPAttemptsLimitExceeded
+}
+
+// newTokenError wraps an APIError into a TokenError if it carries a token-related financial error code.
+func newTokenError(apiErr *APIError) error {
+	code := apiErr.Financial()
+	if code < FinancialInvalidTokenMerchant || code > FinancialIPAttemptsLimitExceeded {
+		return apiErr
+	}
+
+	return &TokenError{APIError: apiErr}
+}
+
 // ConvertToAPIError converts an error to *APIError type if possible.
 func ConvertToAPIError(err error) (*APIError, error) {
-	apiErr, ok := err.(*APIError)
-	if !ok {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
 		return nil, fmt.Errorf("failed to convert error to APIError: %w", err)
 	}
 	return apiErr, nil
@@ -139,8 +344,15 @@ func ConvertToAPIError(err error) (*APIError, error) {
 
 // ErrorRefersToAPI checks if the error refers to an APIError.
 func ErrorRefersToAPI(err error) bool {
-	if _, ok := err.(*APIError); ok {
-		return true
+	var apiErr *APIError
+	return errors.As(err, &apiErr)
+}
+
+// contextError returns the context error wrapped with the client prefix, or nil if the context is still active.
+// It keeps cancellation and deadline errors distinct from APIError and reachable with errors.Is.
+func contextError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("liqpay client: %w", err)
 	}
-	return false
+	return nil
 }
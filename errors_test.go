@@ -0,0 +1,86 @@
+package liqpay
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIErrorClassification(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            APIError
+		wantCategory   ErrorCategory
+		wantFinancial  FinancialError
+		wantRetryable  bool
+		wantCustomer   bool
+		wantIsNotFound bool
+	}{
+		{name: "empty", err: APIError{Status: "error"}, wantCategory: ErrorCategoryUnknown},
+		{name: "payment_processing", err: APIError{Code: "payment_processing"}, wantCategory: ErrorCategoryNonFinancial, wantRetryable: true},
+		{name: "wait_info", err: APIError{Code: "wait_info"}, wantCategory: ErrorCategoryNonFinancial, wantRetryable: true},
+		{name: "payment_not_found", err: APIError{Code: "payment_not_found"}, wantCategory: ErrorCategoryNonFinancial, wantIsNotFound: true},
+		{name: "expired_otp", err: APIError{Code: "expired_otp"}, wantCategory: ErrorCategoryNonFinancial, wantCustomer: true},
+		{name: "general error", err: APIError{Code: "90"}, wantCategory: ErrorCategoryFinancial, wantFinancial: FinancialGeneralError},
+		{name: "ip attempts limit", err: APIError{Code: "109"}, wantCategory: ErrorCategoryFinancial, wantFinancial: 109, wantCustomer: true},
+		{name: "payment declined", err: APIError{Code: "limit", Erc: "9854"}, wantCategory: ErrorCategoryAntiFraud, wantFinancial: FinancialPaymentDeclined, wantCustomer: true},
+		{name: "erc with text code", err: APIError{Code: "err_payment", Erc: "9851"}, wantCategory: ErrorCategoryFinancial, wantFinancial: FinancialCardExpirationExpired, wantCustomer: true},
+		{name: "3ds not supported", err: APIError{Code: "5"}, wantCategory: ErrorCategoryNonFinancial, wantCustomer: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Category(); got != tt.wantCategory {
+				t.Errorf("Category() = %s, want %s", got, tt.wantCategory)
+			}
+			if got := tt.err.Financial(); got != tt.wantFinancial {
+				t.Errorf("Financial() = %d, want %d", got, tt.wantFinancial)
+			}
+			if got := tt.err.Retryable(); got != tt.wantRetryable {
+				t.Errorf("Retryable() = %t, want %t", got, tt.wantRetryable)
+			}
+			if got := tt.err.CustomerFacing(); got != tt.wantCustomer {
+				t.Errorf("CustomerFacing() = %t, want %t", got, tt.wantCustomer)
+			}
+			if got := errors.Is(&tt.err, ErrPaymentNotFound); got != tt.wantIsNotFound {
+				t.Errorf("errors.Is(ErrPaymentNotFound) = %t, want %t", got, tt.wantIsNotFound)
+			}
+		})
+	}
+}
+
+func TestTokenError(t *testing.T) {
+	tests := []struct {
+		code            string
+		wantToken       bool
+		wantRetryable   bool
+		wantRequiresNew bool
+	}{
+		{code: "101", wantToken: true, wantRequiresNew: true},
+		{code: "103", wantToken: true, wantRequiresNew: true},
+		{code: "109", wantToken: true, wantRetryable: true},
+		{code: "90"},
+		{code: "payment_processing", wantRetryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := newTokenError(&APIError{Status: "failure", Code: tt.code})
+
+			var tokenErr *TokenError
+			if got := errors.As(err, &tokenErr); got != tt.wantToken {
+				t.Fatalf("errors.As(*TokenError) = %t, want %t", got, tt.wantToken)
+			}
+			if tt.wantToken {
+				if got := tokenErr.Retryable(); got != tt.wantRetryable {
+					t.Errorf("Retryable() = %t, want %t", got, tt.wantRetryable)
+				}
+				if got := tokenErr.RequiresNewCard(); got != tt.wantRequiresNew {
+					t.Errorf("RequiresNewCard() = %t, want %t", got, tt.wantRequiresNew)
+				}
+			}
+			if got := IsRetryableError(err); got != tt.wantRetryable {
+				t.Errorf("IsRetryableError() = %t, want %t", got, tt.wantRetryable)
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/kabachoksolutions/liqpay
 
-go 1.18
+go 1.24
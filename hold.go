@@ -0,0 +1,87 @@
+package liqpay
+
+import "context"
+
+// CreateHoldCheckout creates a new checkout link for a two-stage payment.
+// The payment amount is held on the sender's account until CompleteHold or CancelHold is called.
+func (c client) CreateHoldCheckout(data *CheckoutRequest) (string, error) {
+	return c.CreateHoldCheckoutContext(context.Background(), data)
+}
+
+// CreateHoldCheckoutContext creates a new checkout link for a two-stage payment using the provided context.
+func (c client) CreateHoldCheckoutContext(ctx context.Context, data *CheckoutRequest) (string, error) {
+	data.Action = ActionHold
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return "", err
+	}
+
+	if err := validateSplitRules(data.Amount, data.SplitRules); err != nil {
+		return "", err
+	}
+
+	resp, err := c.sendClientRequest(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	link, err := c.getClientRedirectURL(resp)
+	if err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+// CompleteHold completes a two-stage payment by capturing the held amount.
+// If amount is zero, the whole held amount is captured; otherwise only the given part is captured.
+func (c client) CompleteHold(orderID string, amount Amount) (*HoldResponse, error) {
+	return c.CompleteHoldContext(context.Background(), orderID, amount)
+}
+
+// CompleteHoldContext completes a two-stage payment using the provided context.
+func (c client) CompleteHoldContext(ctx context.Context, orderID string, amount Amount) (*HoldResponse, error) {
+	data := &HoldCompletionRequest{Action: ActionHoldCompletion, OrderID: orderID, Amount: amount}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &HoldResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// CancelHold releases the amount held by a two-stage payment.
+func (c client) CancelHold(orderID string) (*HoldResponse, error) {
+	return c.CancelHoldContext(context.Background(), orderID)
+}
+
+// CancelHoldContext releases the amount held by a two-stage payment using the provided context.
+func (c client) CancelHoldContext(ctx context.Context, orderID string) (*HoldResponse, error) {
+	data := &HoldCancelRequest{Action: ActionRefund, OrderID: orderID}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &HoldResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,521 @@
+package liqpay
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// IdempotencyRecord is a stored result of a money-moving call.
+type IdempotencyRecord struct {
+	Response  json.RawMessage `json:"response,omitempty"` // JSON-encoded response of the call
+	Err       *APIError       `json:"error,omitempty"`    // API error returned by the call, if any
+	Pending   bool            `json:"pending,omitempty"`  // Whether the call failed without a response, so its outcome is unknown
+	CreatedAt time.Time       `json:"created_at"`         // Time the call was made
+}
+
+// IdempotencyStore stores results of money-moving calls by idempotency key.
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Get returns the record stored by key, or nil if there is no record or it has expired.
+	Get(ctx context.Context, key string) (*IdempotencyRecord, error)
+	// Set stores the record by key for the ttl duration.
+	Set(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]memoryIdempotencyEntry
+}
+
+// NewMemoryIdempotencyStore creates a new in-memory IdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]memoryIdempotencyEntry)}
+}
+
+// Get returns the record stored by key, or nil if there is no record or it has expired.
+func (s *MemoryIdempotencyStore) Get(_ context.Context, key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(s.records, key)
+		return nil, nil
+	}
+
+	record := entry.record
+	return &record, nil
+}
+
+// Set stores the record by key for the ttl duration. Expired records are evicted on write.
+func (s *MemoryIdempotencyStore) Set(_ context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range s.records {
+		if now.After(entry.expiresAt) {
+			delete(s.records, k)
+		}
+	}
+
+	s.records[key] = memoryIdempotencyEntry{record: *record, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// idempotencyLocks serializes concurrent calls with the same idempotency key within the process.
+type idempotencyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*idempotencyLock
+}
+
+type idempotencyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (l *idempotencyLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*idempotencyLock)
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &idempotencyLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// idempotentClient guards money-moving calls of the wrapped client against duplicates.
+type idempotentClient struct {
+	Client
+
+	store  IdempotencyStore
+	window time.Duration
+	locks  idempotencyLocks
+}
+
+// NewIdempotentClient wraps the client so that duplicate money-moving calls
+// (refunds, hold completion and cancellation, invoices, subscription changes,
+// card, wallet, QR, cash, token and split payments, and OTP, CVV and DCC confirmations of card payments)
+// within the window return the stored result instead of calling LiqPay API again.
+//
+// Calls are considered duplicates if they have the same method, order ID and
+// request parameters. Fields that are redacted in logs, such as card details, CVV and
+// confirmation codes, are not part of the fingerprint.
+// Successful calls and calls that failed with a non-retryable APIError are stored and replayed.
+// Calls that failed with a transient API error (see APIError.Retryable) are not stored and can be repeated.
+// Calls that failed without a response, e.g. because of a network error or a canceled context, are stored as pending:
+// a repeated call checks the payment status first and is sent again only if the status shows that
+// the previous call was not applied, otherwise it fails with ErrOutcomeUnknown.
+// Rejected OTP, CVV and DCC confirmations are not stored, so a corrected code can be sent with the same token.
+//
+// Concurrent calls with the same fingerprint are serialized within the process only.
+// A store shared by several processes or replicas does not prevent them from making
+// the same call at the same time, unless the store itself implements a compare-and-set
+// of the key, e.g. by reserving it in Get.
+func NewIdempotentClient(c Client, store IdempotencyStore, window time.Duration) Client {
+	return &idempotentClient{
+		Client: c,
+		store:  store,
+		window: window,
+	}
+}
+
+// ErrOutcomeUnknown is returned by the client of NewIdempotentClient when a previous call with the same
+// parameters failed without a response from LiqPay API, and the payment status does not show that it was not applied.
+var ErrOutcomeUnknown = errors.New("liqpay: outcome of a previous call is unknown")
+
+// idempotentCall describes a money-moving call guarded by the idempotent client.
+type idempotentCall struct {
+	method  string
+	orderID string                     // Order ID of the payment, empty for confirmations
+	token   string                     // Confirmation token, set for confirmations only
+	req     any                        // Request parameters, sensitive fields are not part of the fingerprint
+	applied func(*StatusResponse) bool // Reports whether the payment status shows that the call may have been applied
+}
+
+// mayBeApplied is used for calls whose effect cannot be told from the payment status once the payment exists.
+func mayBeApplied(*StatusResponse) bool {
+	return true
+}
+
+// holdSettled reports whether the hold is no longer waiting for completion or cancellation.
+func holdSettled(s *StatusResponse) bool {
+	return s.Status != StatusHoldWait
+}
+
+// subscriptionRemoved reports whether the subscription is no longer active.
+func subscriptionRemoved(s *StatusResponse) bool {
+	return s.Status != StatusSubscribed
+}
+
+// invoiceCanceled reports whether the invoice is no longer waiting for payment.
+func invoiceCanceled(s *StatusResponse) bool {
+	return s.Status != StatusInvoiceWait
+}
+
+// idempotencyKey builds the idempotency key of a call from the method name, order ID and request parameters.
+// Fields that are redacted in logs, such as card details, CVV and confirmation codes, are not part of the key.
+// Confirmations are scoped to the SHA-256 digest of the confirmation token instead of an order ID.
+func idempotencyKey(call idempotentCall) (string, error) {
+	payload, err := json.Marshal(call.req)
+	if err != nil {
+		return "", fmt.Errorf("liqpay client: failed to encode idempotency fingerprint: %w", err)
+	}
+
+	var fields map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return "", fmt.Errorf("liqpay client: failed to encode idempotency fingerprint: %w", err)
+	}
+	delete(fields, "action")
+	for key := range fields {
+		if sensitiveFields[key] {
+			delete(fields, key)
+		}
+	}
+
+	// json.Marshal sorts map keys, so the fingerprint is stable.
+	payload, err = json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("liqpay client: failed to encode idempotency fingerprint: %w", err)
+	}
+
+	scope := call.orderID
+	if call.token != "" {
+		sum := sha256.Sum256([]byte(call.token))
+		scope = "token-" + hex.EncodeToString(sum[:])
+	}
+
+	sum := sha256.Sum256(payload)
+	return "liqpay:" + call.method + ":" + scope + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// isUnknownOutcome reports whether the call failed after its request may have reached LiqPay API:
+// a network error, a canceled context, a 5xx HTTP response or an unreadable response.
+func isUnknownOutcome(err error) bool {
+	var (
+		urlErr  *url.Error
+		httpErr *HTTPError
+		respErr *ResponseError
+	)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode != http.StatusTooManyRequests
+	}
+	return errors.As(err, &urlErr) || errors.As(err, &respErr)
+}
+
+// guard executes the call unless a result for the same idempotency key is stored, in which case the stored result is returned.
+// Transient API errors are not stored, so the call can be repeated after them. Calls that failed without a response
+// are stored as pending and repeated only if the payment status shows that they were not applied.
+func guard[T any](ctx context.Context, c *idempotentClient, call idempotentCall, do func() (*T, error)) (*T, error) {
+	key, err := idempotencyKey(call)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := c.locks.lock(key)
+	defer unlock()
+
+	record, err := c.store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to get idempotency record: %w", err)
+	}
+
+	switch {
+	case record != nil && record.Pending:
+		if err := c.resolvePending(ctx, call); err != nil {
+			return nil, err
+		}
+	case record != nil:
+		return replay[T](call.method, record)
+	}
+
+	v, callErr := do()
+	switch {
+	case callErr != nil && isUnknownOutcome(callErr):
+		record = &IdempotencyRecord{Pending: true, CreatedAt: time.Now()}
+		if err := c.store.Set(ctx, key, record, c.window); err != nil {
+			return v, fmt.Errorf("liqpay client: failed to set idempotency record: %w", err)
+		}
+		return v, callErr
+	case callErr != nil && (!ErrorRefersToAPI(callErr) || IsRetryableError(callErr)):
+		return v, callErr
+	case callErr != nil && call.token != "":
+		// The confirmation code is not part of the key, so a rejected code can be corrected and sent again.
+		return v, callErr
+	}
+
+	record = &IdempotencyRecord{CreatedAt: time.Now()}
+	if v != nil {
+		if record.Response, err = json.Marshal(v); err != nil {
+			return v, fmt.Errorf("liqpay client: failed to encode idempotency record: %w", err)
+		}
+	}
+	if callErr != nil {
+		record.Err, _ = ConvertToAPIError(callErr)
+	}
+
+	if err := c.store.Set(ctx, key, record, c.window); err != nil {
+		return v, fmt.Errorf("liqpay client: failed to set idempotency record: %w", err)
+	}
+
+	return v, callErr
+}
+
+// resolvePending checks with the payment status whether a call that failed without a response was applied.
+// It returns nil if the call was not applied and can be sent again, or ErrOutcomeUnknown otherwise.
+func (c *idempotentClient) resolvePending(ctx context.Context, call idempotentCall) error {
+	if call.orderID == "" || call.applied == nil {
+		return ErrOutcomeUnknown
+	}
+
+	status, err := c.Client.StatusContext(ctx, call.orderID)
+	switch {
+	case errors.Is(err, ErrPaymentNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("liqpay client: failed to resolve the outcome of a previous call: %w", err)
+	case call.applied(status):
+		return ErrOutcomeUnknown
+	}
+	return nil
+}
+
+// replay returns the result stored in the idempotency record.
+func replay[T any](method string, record *IdempotencyRecord) (*T, error) {
+	var v *T
+	if len(record.Response) > 0 {
+		v = new(T)
+		if err := json.Unmarshal(record.Response, v); err != nil {
+			return nil, fmt.Errorf("liqpay client: failed to decode idempotency record: %w", err)
+		}
+	}
+
+	if record.Err == nil {
+		return v, nil
+	}
+
+	if method == "PayWithToken" {
+		return v, newTokenError(record.Err)
+	}
+	return v, record.Err
+}
+
+func (c *idempotentClient) CompleteHold(orderID string, amount Amount) (*HoldResponse, error) {
+	return c.CompleteHoldContext(context.Background(), orderID, amount)
+}
+
+func (c *idempotentClient) CompleteHoldContext(ctx context.Context, orderID string, amount Amount) (*HoldResponse, error) {
+	req := &HoldCompletionRequest{OrderID: orderID, Amount: amount}
+	return guard(ctx, c, idempotentCall{method: "CompleteHold", orderID: orderID, req: req, applied: holdSettled}, func() (*HoldResponse, error) {
+		return c.Client.CompleteHoldContext(ctx, orderID, amount)
+	})
+}
+
+func (c *idempotentClient) CancelHold(orderID string) (*HoldResponse, error) {
+	return c.CancelHoldContext(context.Background(), orderID)
+}
+
+func (c *idempotentClient) CancelHoldContext(ctx context.Context, orderID string) (*HoldResponse, error) {
+	req := &HoldCancelRequest{OrderID: orderID}
+	return guard(ctx, c, idempotentCall{method: "CancelHold", orderID: orderID, req: req, applied: holdSettled}, func() (*HoldResponse, error) {
+		return c.Client.CancelHoldContext(ctx, orderID)
+	})
+}
+
+func (c *idempotentClient) SplitPayment(req *SplitPaymentRequest) (*StatusResponse, error) {
+	return c.SplitPaymentContext(context.Background(), req)
+}
+
+func (c *idempotentClient) SplitPaymentContext(ctx context.Context, req *SplitPaymentRequest) (*StatusResponse, error) {
+	return guard(ctx, c, idempotentCall{method: "SplitPayment", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*StatusResponse, error) {
+		return c.Client.SplitPaymentContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) PayWithToken(req *TokenPaymentRequest) (*TokenPaymentResponse, error) {
+	return c.PayWithTokenContext(context.Background(), req)
+}
+
+func (c *idempotentClient) PayWithTokenContext(ctx context.Context, req *TokenPaymentRequest) (*TokenPaymentResponse, error) {
+	return guard(ctx, c, idempotentCall{method: "PayWithToken", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*TokenPaymentResponse, error) {
+		return c.Client.PayWithTokenContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) PayByCard(req *CardPaymentRequest) (*PaymentStepResult, error) {
+	return c.PayByCardContext(context.Background(), req)
+}
+
+func (c *idempotentClient) PayByCardContext(ctx context.Context, req *CardPaymentRequest) (*PaymentStepResult, error) {
+	return guard(ctx, c, idempotentCall{method: "PayByCard", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*PaymentStepResult, error) {
+		return c.Client.PayByCardContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) PayWithApplePay(req *WalletPaymentRequest) (*PaymentStepResult, error) {
+	return c.PayWithApplePayContext(context.Background(), req)
+}
+
+func (c *idempotentClient) PayWithApplePayContext(ctx context.Context, req *WalletPaymentRequest) (*PaymentStepResult, error) {
+	return guard(ctx, c, idempotentCall{method: "PayWithApplePay", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*PaymentStepResult, error) {
+		return c.Client.PayWithApplePayContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) PayWithGooglePay(req *WalletPaymentRequest) (*PaymentStepResult, error) {
+	return c.PayWithGooglePayContext(context.Background(), req)
+}
+
+func (c *idempotentClient) PayWithGooglePayContext(ctx context.Context, req *WalletPaymentRequest) (*PaymentStepResult, error) {
+	return guard(ctx, c, idempotentCall{method: "PayWithGooglePay", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*PaymentStepResult, error) {
+		return c.Client.PayWithGooglePayContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) ConfirmOTP(token string, otp string) (*PaymentStepResult, error) {
+	return c.ConfirmOTPContext(context.Background(), token, otp)
+}
+
+func (c *idempotentClient) ConfirmOTPContext(ctx context.Context, token string, otp string) (*PaymentStepResult, error) {
+	req := &OTPConfirmRequest{Token: token, OTP: otp}
+	return guard(ctx, c, idempotentCall{method: "ConfirmOTP", token: token, req: req}, func() (*PaymentStepResult, error) {
+		return c.Client.ConfirmOTPContext(ctx, token, otp)
+	})
+}
+
+func (c *idempotentClient) ConfirmCVV(token string, cvv string) (*PaymentStepResult, error) {
+	return c.ConfirmCVVContext(context.Background(), token, cvv)
+}
+
+func (c *idempotentClient) ConfirmCVVContext(ctx context.Context, token string, cvv string) (*PaymentStepResult, error) {
+	req := &CVVConfirmRequest{Token: token, CVV: cvv}
+	return guard(ctx, c, idempotentCall{method: "ConfirmCVV", token: token, req: req}, func() (*PaymentStepResult, error) {
+		return c.Client.ConfirmCVVContext(ctx, token, cvv)
+	})
+}
+
+func (c *idempotentClient) ConfirmDCC(token string, accept bool) (*PaymentStepResult, error) {
+	return c.ConfirmDCCContext(context.Background(), token, accept)
+}
+
+func (c *idempotentClient) ConfirmDCCContext(ctx context.Context, token string, accept bool) (*PaymentStepResult, error) {
+	req := &DCCConfirmRequest{Token: token, DCC: strconv.FormatBool(accept)}
+	return guard(ctx, c, idempotentCall{method: "ConfirmDCC", token: token, req: req}, func() (*PaymentStepResult, error) {
+		return c.Client.ConfirmDCCContext(ctx, token, accept)
+	})
+}
+
+func (c *idempotentClient) CreateQRPayment(req *QRPaymentRequest) (*QRPaymentResponse, error) {
+	return c.CreateQRPaymentContext(context.Background(), req)
+}
+
+func (c *idempotentClient) CreateQRPaymentContext(ctx context.Context, req *QRPaymentRequest) (*QRPaymentResponse, error) {
+	return guard(ctx, c, idempotentCall{method: "CreateQRPayment", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*QRPaymentResponse, error) {
+		return c.Client.CreateQRPaymentContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) CreateCashPayment(req *CashPaymentRequest) (*CashPaymentResponse, error) {
+	return c.CreateCashPaymentContext(context.Background(), req)
+}
+
+func (c *idempotentClient) CreateCashPaymentContext(ctx context.Context, req *CashPaymentRequest) (*CashPaymentResponse, error) {
+	return guard(ctx, c, idempotentCall{method: "CreateCashPayment", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*CashPaymentResponse, error) {
+		return c.Client.CreateCashPaymentContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) UpdateSubscription(req *EditSubscriptionRequest) (*SubscriptionResponse, error) {
+	return c.UpdateSubscriptionContext(context.Background(), req)
+}
+
+func (c *idempotentClient) UpdateSubscriptionContext(ctx context.Context, req *EditSubscriptionRequest) (*SubscriptionResponse, error) {
+	return guard(ctx, c, idempotentCall{method: "UpdateSubscription", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*SubscriptionResponse, error) {
+		return c.Client.UpdateSubscriptionContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) RemoveSubscription(orderID string) (*SubscriptionResponse, error) {
+	return c.RemoveSubscriptionContext(context.Background(), orderID)
+}
+
+func (c *idempotentClient) RemoveSubscriptionContext(ctx context.Context, orderID string) (*SubscriptionResponse, error) {
+	req := &UnsubscribeRequest{OrderID: orderID}
+	return guard(ctx, c, idempotentCall{method: "RemoveSubscription", orderID: orderID, req: req, applied: subscriptionRemoved}, func() (*SubscriptionResponse, error) {
+		return c.Client.RemoveSubscriptionContext(ctx, orderID)
+	})
+}
+
+func (c *idempotentClient) CreateInvoice(req *InvoiceRequest) (*InvoiceResponse, error) {
+	return c.CreateInvoiceContext(context.Background(), req)
+}
+
+func (c *idempotentClient) CreateInvoiceContext(ctx context.Context, req *InvoiceRequest) (*InvoiceResponse, error) {
+	return guard(ctx, c, idempotentCall{method: "CreateInvoice", orderID: req.OrderID, req: req, applied: mayBeApplied}, func() (*InvoiceResponse, error) {
+		return c.Client.CreateInvoiceContext(ctx, req)
+	})
+}
+
+func (c *idempotentClient) CancelInvoice(orderID string) (*CancelInvoiceResponse, error) {
+	return c.CancelInvoiceContext(context.Background(), orderID)
+}
+
+func (c *idempotentClient) CancelInvoiceContext(ctx context.Context, orderID string) (*CancelInvoiceResponse, error) {
+	req := &CancelInvoiceRequest{OrderID: orderID}
+	return guard(ctx, c, idempotentCall{method: "CancelInvoice", orderID: orderID, req: req, applied: invoiceCanceled}, func() (*CancelInvoiceResponse, error) {
+		return c.Client.CancelInvoiceContext(ctx, orderID)
+	})
+}
+
+func (c *idempotentClient) Refund(orderID string, amount Amount) (*RefundResponse, error) {
+	return c.RefundContext(context.Background(), orderID, amount)
+}
+
+func (c *idempotentClient) RefundContext(ctx context.Context, orderID string, amount Amount) (*RefundResponse, error) {
+	req := &RefundRequest{OrderID: orderID, Amount: amount}
+	return guard(ctx, c, idempotentCall{method: "Refund", orderID: orderID, req: req, applied: mayBeApplied}, func() (*RefundResponse, error) {
+		return c.Client.RefundContext(ctx, orderID, amount)
+	})
+}
@@ -0,0 +1,83 @@
+package liqpay
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIdempotencyKeyExcludesSensitiveFields(t *testing.T) {
+	for field := range sensitiveFields {
+		t.Run(field, func(t *testing.T) {
+			call := func(value string) idempotentCall {
+				return idempotentCall{method: "Refund", orderID: "order-1", req: map[string]any{"amount": 10, field: value}}
+			}
+
+			first, err := idempotencyKey(call("4242424242424242"))
+			if err != nil {
+				t.Fatalf("idempotencyKey() error = %v", err)
+			}
+			second, err := idempotencyKey(call("5555555555554444"))
+			if err != nil {
+				t.Fatalf("idempotencyKey() error = %v", err)
+			}
+			if first != second {
+				t.Errorf("idempotencyKey() = %s and %s for different %s values, want equal keys", first, second, field)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	key := func(call idempotentCall) string {
+		t.Helper()
+		k, err := idempotencyKey(call)
+		if err != nil {
+			t.Fatalf("idempotencyKey() error = %v", err)
+		}
+		return k
+	}
+
+	refund := idempotentCall{method: "Refund", orderID: "order-1", req: &RefundRequest{Action: ActionRefund, OrderID: "order-1", Amount: MustParseAmount("10")}}
+	tests := []struct {
+		name  string
+		call  idempotentCall
+		equal bool
+	}{
+		{name: "same parameters", call: refund, equal: true},
+		{name: "action is ignored", call: idempotentCall{method: "Refund", orderID: "order-1", req: &RefundRequest{OrderID: "order-1", Amount: MustParseAmount("10")}}, equal: true},
+		{name: "different amount", call: idempotentCall{method: "Refund", orderID: "order-1", req: &RefundRequest{OrderID: "order-1", Amount: MustParseAmount("11")}}},
+		{name: "different order", call: idempotentCall{method: "Refund", orderID: "order-2", req: &RefundRequest{OrderID: "order-2", Amount: MustParseAmount("10")}}},
+		{name: "different method", call: idempotentCall{method: "CompleteHold", orderID: "order-1", req: &HoldCompletionRequest{OrderID: "order-1", Amount: MustParseAmount("10")}}},
+	}
+
+	want := key(refund)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := key(tt.call); (got == want) != tt.equal {
+				t.Errorf("idempotencyKey() = %s, want equal to %s: %t", got, want, tt.equal)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyConfirmation(t *testing.T) {
+	confirm := func(token, otp string) string {
+		t.Helper()
+		k, err := idempotencyKey(idempotentCall{method: "ConfirmOTP", token: token, req: &OTPConfirmRequest{Token: token, OTP: otp}})
+		if err != nil {
+			t.Fatalf("idempotencyKey() error = %v", err)
+		}
+		return k
+	}
+
+	key := confirm("confirm-token-1", "123456")
+	if strings.Contains(key, "confirm-token-1") || strings.Contains(key, "123456") {
+		t.Errorf("idempotencyKey() = %s, want no confirmation token or code in the key", key)
+	}
+	if other := confirm("confirm-token-1", "654321"); other != key {
+		t.Errorf("idempotencyKey() = %s for another code, want %s", other, key)
+	}
+	if other := confirm("confirm-token-2", "123456"); other == key {
+		t.Errorf("idempotencyKey() = %s for another token, want a different key", other)
+	}
+}
@@ -0,0 +1,250 @@
+package liqpay_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kabachoksolutions/liqpay"
+	"github.com/kabachoksolutions/liqpay/liqpaytest"
+)
+
+func TestIdempotentClientRefund(t *testing.T) {
+	tests := []struct {
+		name         string
+		failWith     string        // err_code of the first refund, if any
+		wantErr      bool          // Whether the repeated refund fails
+		wantRefunded liqpay.Amount // Refunded amount after both refunds
+	}{
+		{name: "success is replayed", wantRefunded: liqpay.MustParseAmount("10")},
+		{name: "final error is replayed", failWith: string(liqpay.NonFinancialAccessError), wantErr: true},
+		{name: "payment_processing is not stored", failWith: string(liqpay.NonFinancialPaymentProcessing), wantRefunded: liqpay.MustParseAmount("10")},
+		{name: "wait_info is not stored", failWith: string(liqpay.NonFinancialAdditionalInfoRequired), wantRefunded: liqpay.MustParseAmount("10")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := liqpaytest.NewServer("public", "private")
+			defer srv.Close()
+
+			srv.AddOrder(liqpaytest.Order{
+				OrderID:  "order-1",
+				Action:   liqpay.ActionPay,
+				Status:   liqpay.StatusSuccess,
+				Amount:   liqpay.MustParseAmount("100"),
+				Currency: liqpay.CurrencyUAH,
+			})
+			if tt.failWith != "" {
+				srv.FailNext(liqpay.ActionRefund, tt.failWith)
+			}
+
+			c := liqpay.NewIdempotentClient(liqpay.NewClient(srv.Config(), nil), liqpay.NewMemoryIdempotencyStore(), time.Hour)
+
+			first, firstErr := c.Refund("order-1", liqpay.MustParseAmount("10"))
+			second, secondErr := c.Refund("order-1", liqpay.MustParseAmount("10"))
+
+			if tt.failWith == "" {
+				if firstErr != nil || secondErr != nil {
+					t.Fatalf("Refund() errors = %v, %v, want nil", firstErr, secondErr)
+				}
+				if *first != *second {
+					t.Errorf("repeated Refund() = %+v, want %+v", second, first)
+				}
+			}
+			if (secondErr != nil) != tt.wantErr {
+				t.Errorf("repeated Refund() error = %v, want error %t", secondErr, tt.wantErr)
+			}
+			if tt.wantErr && secondErr.Error() != firstErr.Error() {
+				t.Errorf("repeated Refund() error = %v, want %v", secondErr, firstErr)
+			}
+
+			order, _ := srv.Order("order-1")
+			if !order.RefundedAmount.Equal(tt.wantRefunded) {
+				t.Errorf("refunded amount = %s, want %s", order.RefundedAmount, tt.wantRefunded)
+			}
+		})
+	}
+}
+
+func TestIdempotentClientConfirm(t *testing.T) {
+	tests := []struct {
+		name         string
+		verification liqpay.Status
+		confirm      func(c liqpay.Client, token string) (*liqpay.PaymentStepResult, error)
+	}{
+		{
+			name:         "otp",
+			verification: liqpay.StatusOTPVerify,
+			confirm: func(c liqpay.Client, token string) (*liqpay.PaymentStepResult, error) {
+				return c.ConfirmOTP(token, "123456")
+			},
+		},
+		{
+			name:         "cvv",
+			verification: liqpay.StatusCVVVerify,
+			confirm: func(c liqpay.Client, token string) (*liqpay.PaymentStepResult, error) {
+				return c.ConfirmCVV(token, "123")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := liqpaytest.NewServer("public", "private")
+			defer srv.Close()
+			srv.SetCardVerification("4242424242424242", tt.verification)
+
+			c := liqpay.NewIdempotentClient(liqpay.NewClient(srv.Config(), nil), liqpay.NewMemoryIdempotencyStore(), time.Hour)
+
+			step, err := c.PayByCard(&liqpay.CardPaymentRequest{
+				Amount:      liqpay.MustParseAmount("100"),
+				Card:        "4242424242424242",
+				Currency:    liqpay.CurrencyUAH,
+				Description: "Test",
+				IP:          "127.0.0.1",
+				OrderID:     "order-1",
+			})
+			if err != nil {
+				t.Fatalf("PayByCard() error = %v", err)
+			}
+
+			// The fake server invalidates the token after the first confirmation,
+			// so the repeated call succeeds only if it is replayed.
+			for i := range 2 {
+				step, err := tt.confirm(c, step.Token)
+				if err != nil {
+					t.Fatalf("confirmation %d error = %v", i+1, err)
+				}
+				if step.Response.Status != liqpay.StatusSuccess {
+					t.Errorf("confirmation %d status = %s, want %s", i+1, step.Response.Status, liqpay.StatusSuccess)
+				}
+			}
+		})
+	}
+}
+
+// lossyTransport fails one request with a network error, either before it reaches the server
+// or after the server has handled it and the response is lost.
+type lossyTransport struct {
+	lose         int  // Number of the request to fail, starting from 1
+	loseResponse bool // Whether the request reaches the server before it fails
+	requests     int
+}
+
+func (t *lossyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	if t.requests != t.lose {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	if t.loseResponse {
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+	return nil, errors.New("connection reset by peer")
+}
+
+func TestIdempotentClientPending(t *testing.T) {
+	held := liqpaytest.Order{OrderID: "order-1", Action: liqpay.ActionHold, Status: liqpay.StatusHoldWait, Amount: liqpay.MustParseAmount("100"), Currency: liqpay.CurrencyUAH}
+	paid := liqpaytest.Order{OrderID: "order-1", Action: liqpay.ActionPay, Status: liqpay.StatusSuccess, Amount: liqpay.MustParseAmount("100"), Currency: liqpay.CurrencyUAH}
+
+	completeHold := func(c liqpay.Client) error {
+		_, err := c.CompleteHold("order-1", liqpay.Amount{})
+		return err
+	}
+	refund := func(c liqpay.Client) error {
+		_, err := c.Refund("order-1", liqpay.MustParseAmount("10"))
+		return err
+	}
+	createInvoice := func(c liqpay.Client) error {
+		_, err := c.CreateInvoice(&liqpay.InvoiceRequest{
+			Amount:      liqpay.MustParseAmount("100"),
+			Currency:    liqpay.CurrencyUAH,
+			Description: "Test",
+			Email:       "test@example.com",
+			OrderID:     "order-1",
+		})
+		return err
+	}
+
+	tests := []struct {
+		name         string
+		order        *liqpaytest.Order
+		call         func(c liqpay.Client) error
+		loseResponse bool
+		wantErr      error         // Error of the repeated call
+		wantStatus   liqpay.Status // Status of the order after both calls
+		wantRefunded liqpay.Amount
+	}{
+		{name: "hold completion not sent is sent again", order: &held, call: completeHold, wantStatus: liqpay.StatusSuccess},
+		{name: "hold completion applied is not sent again", order: &held, call: completeHold, loseResponse: true, wantErr: liqpay.ErrOutcomeUnknown, wantStatus: liqpay.StatusSuccess},
+		{name: "refund is not sent again", order: &paid, call: refund, loseResponse: true, wantErr: liqpay.ErrOutcomeUnknown, wantStatus: liqpay.StatusSuccess, wantRefunded: liqpay.MustParseAmount("10")},
+		{name: "invoice not found is sent again", call: createInvoice, wantStatus: liqpay.StatusInvoiceWait},
+		{name: "invoice created is not sent again", call: createInvoice, loseResponse: true, wantErr: liqpay.ErrOutcomeUnknown, wantStatus: liqpay.StatusInvoiceWait},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := liqpaytest.NewServer("public", "private")
+			defer srv.Close()
+			if tt.order != nil {
+				srv.AddOrder(*tt.order)
+			}
+
+			transport := &lossyTransport{lose: 1, loseResponse: tt.loseResponse}
+			c := liqpay.NewIdempotentClient(liqpay.NewClient(srv.Config(), &http.Client{Transport: transport}), liqpay.NewMemoryIdempotencyStore(), time.Hour)
+
+			if err := tt.call(c); err == nil {
+				t.Fatal("first call error = nil, want a network error")
+			}
+			if err := tt.call(c); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("repeated call error = %v, want %v", err, tt.wantErr)
+			}
+
+			order, _ := srv.Order("order-1")
+			if order.Status != tt.wantStatus {
+				t.Errorf("order status = %s, want %s", order.Status, tt.wantStatus)
+			}
+			if !order.RefundedAmount.Equal(tt.wantRefunded) {
+				t.Errorf("refunded amount = %s, want %s", order.RefundedAmount, tt.wantRefunded)
+			}
+		})
+	}
+}
+
+func TestIdempotentClientPendingConfirmation(t *testing.T) {
+	srv := liqpaytest.NewServer("public", "private")
+	defer srv.Close()
+	srv.SetCardVerification("4242424242424242", liqpay.StatusOTPVerify)
+
+	// The payment goes through, and the response of the OTP confirmation is lost.
+	transport := &lossyTransport{lose: 2, loseResponse: true}
+	c := liqpay.NewIdempotentClient(liqpay.NewClient(srv.Config(), &http.Client{Transport: transport}), liqpay.NewMemoryIdempotencyStore(), time.Hour)
+
+	step, err := c.PayByCard(&liqpay.CardPaymentRequest{
+		Amount:      liqpay.MustParseAmount("100"),
+		Card:        "4242424242424242",
+		Currency:    liqpay.CurrencyUAH,
+		Description: "Test",
+		IP:          "127.0.0.1",
+		OrderID:     "order-1",
+	})
+	if err != nil {
+		t.Fatalf("PayByCard() error = %v", err)
+	}
+
+	if _, err := c.ConfirmOTP(step.Token, "123456"); err == nil {
+		t.Fatal("ConfirmOTP() error = nil, want a network error")
+	}
+	if _, err := c.ConfirmOTP(step.Token, "123456"); !errors.Is(err, liqpay.ErrOutcomeUnknown) {
+		t.Errorf("repeated ConfirmOTP() error = %v, want %v", err, liqpay.ErrOutcomeUnknown)
+	}
+	if transport.requests != 2 {
+		t.Errorf("requests = %d, want 2", transport.requests)
+	}
+}
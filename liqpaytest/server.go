@@ -0,0 +1,1135 @@
+// Package liqpaytest provides an in-process fake of the LiqPay API for tests.
+//
+// The fake verifies request signatures, rejects server-server parameters an action does not
+// accept, keeps an in-memory ledger of orders and
+// implements the status, refund, hold, invoice, subscription, card, QR, cash, token and split
+// payment actions with LiqPay-like state transitions, plus the payments archive
+// and compensation register reports over the ledger:
+//
+//	checkout (pay, paysplit) -> CompletePayment -> success -> refund -> reversed
+//	checkout (hold)          -> CompletePayment -> hold_wait -> hold_completion -> success
+//	                                                         -> refund -> reversed
+//	checkout (subscribe)     -> CompletePayment -> subscribed -> unsubscribe -> unsubscribed
+//	invoice_send             -> invoice_wait -> CompletePayment -> success
+//	                                         -> invoice_cancel (removed)
+//	pay (server-server)      -> success, or with SetCardVerification:
+//	                         -> 3ds_verify -> CompletePayment -> success
+//	                         -> otp_verify -> confirm -> success
+//	                         -> cvv_verify -> cvv -> success
+//	                         -> dcc_verify (SetCardDCC) -> confirm -> success or the verification above
+//	payqr                    -> wait_qr -> CompletePayment -> success
+//	paycash                  -> cash_wait -> CompletePayment -> success
+//
+// Until the customer completes a checkout, the status action responds with payment_not_found,
+// just like LiqPay does.
+package liqpaytest
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha1"
+	"crypto/sha3"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kabachoksolutions/liqpay"
+)
+
+// Order represents a payment in the fake server ledger.
+type Order struct {
+	OrderID        string
+	PaymentID      int64
+	Action         liqpay.Action
+	Status         liqpay.Status // Empty until the customer completes the checkout
+	Amount         liqpay.Amount
+	RefundedAmount liqpay.Amount
+	Currency       liqpay.Currency
+	Description    string
+	PayType        liqpay.PayType  // Defaults to card
+	DebitAmount    liqpay.Amount   // Amount debited in DebitCurrency if the customer accepted a DCC offer
+	DebitCurrency  liqpay.Currency // Card currency if the customer accepted a DCC offer
+	CardToken      string
+	ServerURL      string
+	ErrCode        string
+	CreateDate     time.Time
+	EndDate        time.Time
+}
+
+// dccOffer is a dynamic currency conversion offer for a card and, once offered, the status of the payment
+// after the customer's choice.
+type dccOffer struct {
+	currency liqpay.Currency
+	rate     liqpay.Amount
+	next     liqpay.Status
+}
+
+type scriptedError struct {
+	code string
+	desc string
+}
+
+// Server is a fake LiqPay API server backed by httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	PublicKey          string
+	PrivateKey         string
+	SignatureAlgorithm liqpay.SignatureAlgorithm // Algorithm used to verify requests and sign callbacks. Defaults to SHA1
+
+	// CallbackClient is used to send callbacks to server_url. Defaults to http.DefaultClient.
+	CallbackClient *http.Client
+
+	mu            sync.Mutex
+	orders        map[string]*Order
+	cardTokens    map[string]bool
+	cardSteps     map[string]liqpay.Status
+	confirmTokens map[string]string
+	cardDCC       map[string]dccOffer
+	dccOffers     map[string]dccOffer
+	errors        map[liqpay.Action][]scriptedError
+	nextPaymentID int64
+}
+
+// NewServer starts a new fake LiqPay server accepting requests signed with the provided keys.
+// The caller should call Close when finished.
+func NewServer(publicKey, privateKey string) *Server {
+	s := &Server{
+		PublicKey:     publicKey,
+		PrivateKey:    privateKey,
+		orders:        make(map[string]*Order),
+		cardTokens:    make(map[string]bool),
+		cardSteps:     make(map[string]liqpay.Status),
+		confirmTokens: make(map[string]string),
+		cardDCC:       make(map[string]dccOffer),
+		dccOffers:     make(map[string]dccOffer),
+		errors:        make(map[liqpay.Action][]scriptedError),
+		nextPaymentID: 1000000,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/request", s.handleServerRequest)
+	mux.HandleFunc("/api/3/checkout", s.handleCheckout)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Client returns an HTTP client that routes every request to the fake server,
+// regardless of the requested host. It is only needed when the liqpay.Config
+// is not obtained from Config and still points to the LiqPay endpoints.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+
+	return &http.Client{
+		Transport: &rewriteTransport{target: target, base: s.Server.Client().Transport},
+	}
+}
+
+// Config returns a liqpay.Config with the server keys, signature algorithm and endpoints.
+func (s *Server) Config() *liqpay.Config {
+	cfg := liqpay.NewConfig(s.PublicKey, s.PrivateKey, false)
+	cfg.SignatureAlgorithm = s.SignatureAlgorithm
+	cfg.ServerServerURL = s.URL + "/api/request"
+	cfg.ClientServerURL = s.URL + "/api/3/checkout"
+	return cfg
+}
+
+// AddOrder adds an order to the ledger, replacing an existing one with the same order ID.
+func (s *Server) AddOrder(order Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if order.PaymentID == 0 {
+		order.PaymentID = s.newPaymentID()
+	}
+	if order.CreateDate.IsZero() {
+		order.CreateDate = time.Now()
+	}
+	s.orders[order.OrderID] = &order
+}
+
+// Order returns a copy of the order with the given order ID.
+func (s *Server) Order(orderID string) (Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return Order{}, false
+	}
+	return *order, true
+}
+
+// SetStatus sets the status of the order with the given order ID.
+func (s *Server) SetStatus(orderID string, status liqpay.Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return fmt.Errorf("liqpaytest: order %q not found", orderID)
+	}
+	order.Status = status
+	order.EndDate = time.Now()
+	return nil
+}
+
+// CompletePayment simulates the customer completing the payment of a checkout, an invoice
+// or a card payment awaiting customer verification, e.g. 3DS.
+func (s *Server) CompletePayment(orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return fmt.Errorf("liqpaytest: order %q not found", orderID)
+	}
+
+	switch {
+	case order.Status != "" && !order.Status.RequiresCustomerAction():
+		return fmt.Errorf("liqpaytest: order %q is already in status %q", orderID, order.Status)
+	case order.Action == liqpay.ActionHold:
+		order.Status = liqpay.StatusHoldWait
+	case order.Action == liqpay.ActionSubscribe:
+		order.Status = liqpay.StatusSubscribed
+	default:
+		order.Status = liqpay.StatusSuccess
+	}
+	order.EndDate = time.Now()
+
+	return nil
+}
+
+// AddCardToken registers a card token that can be charged with the paytoken action.
+func (s *Server) AddCardToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cardTokens[token] = true
+}
+
+// SetCardVerification makes server-server payments by the card number require customer verification:
+// 3ds_verify (completed with CompletePayment), otp_verify or cvv_verify (completed with the confirm and cvv actions).
+// Payments by other cards succeed immediately.
+func (s *Server) SetCardVerification(card string, status liqpay.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cardSteps[card] = status
+}
+
+// SetCardDCC makes server-server payments by the card number with dcc=Y stop in dcc_verify with the offer
+// to pay in the card currency at the rate, unless the payment is already in that currency.
+// After the confirm action the payment continues to the verification set with SetCardVerification, if any.
+func (s *Server) SetCardDCC(card string, currency liqpay.Currency, rate liqpay.Amount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cardDCC[card] = dccOffer{currency: currency, rate: rate}
+}
+
+// FailNext makes the next request with the given action fail with the given err_code.
+// Numeric codes are also returned as err_erc financial error codes.
+func (s *Server) FailNext(action liqpay.Action, errCode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors[action] = append(s.errors[action], scriptedError{code: errCode, desc: "scripted error"})
+}
+
+// SendCallback sends a signed callback with the current order state to the order server_url.
+func (s *Server) SendCallback(orderID string) error {
+	s.mu.Lock()
+	order, ok := s.orders[orderID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("liqpaytest: order %q not found", orderID)
+	}
+	serverURL := order.ServerURL
+	payload := s.orderFields(order)
+	s.mu.Unlock()
+
+	if serverURL == "" {
+		return fmt.Errorf("liqpaytest: order %q has no server_url", orderID)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("liqpaytest: failed to marshal callback: %w", err)
+	}
+
+	data := base64.StdEncoding.EncodeToString(body)
+	signature, err := s.sign(data)
+	if err != nil {
+		return err
+	}
+
+	httpClient := s.CallbackClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.PostForm(serverURL, url.Values{"data": {data}, "signature": {signature}})
+	if err != nil {
+		return fmt.Errorf("liqpaytest: failed to send callback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("liqpaytest: callback responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// sign generates a signature for the given data using the server private key.
+func (s *Server) sign(data string) (string, error) {
+	var hasher hash.Hash
+
+	switch s.SignatureAlgorithm {
+	case "", liqpay.SignatureAlgorithmSHA1:
+		hasher = sha1.New()
+	case liqpay.SignatureAlgorithmSHA3256:
+		hasher = sha3.New256()
+	default:
+		return "", fmt.Errorf("liqpaytest: unsupported signature algorithm %q", s.SignatureAlgorithm)
+	}
+
+	hasher.Write([]byte(s.PrivateKey))
+	hasher.Write([]byte(data))
+	hasher.Write([]byte(s.PrivateKey))
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// decodeRequest verifies the request signature and decodes the request payload.
+func (s *Server) decodeRequest(r *http.Request) (map[string]any, *scriptedError) {
+	if r.Method != http.MethodPost {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialInvalidRequestPath), desc: "method not allowed"}
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "malformed form"}
+	}
+
+	data, signature := r.PostForm.Get("data"), r.PostForm.Get("signature")
+	expected, err := s.sign(data)
+	if err != nil || subtle.ConstantTimeCompare([]byte(signature), []byte(expected)) != 1 {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialInvalidSignature), desc: "invalid signature"}
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "malformed data"}
+	}
+
+	var payload map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "malformed data"}
+	}
+
+	if stringValue(payload["public_key"]) != s.PublicKey {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPublicKeyNotFound), desc: "public key not found"}
+	}
+
+	if stringValue(payload["version"]) != liqpay.CurrentAPIVersion {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "unsupported version"}
+	}
+
+	switch liqpay.Action(stringValue(payload["action"])) {
+	case liqpay.ActionReports, liqpay.ActionReportsCompensation, liqpay.ActionConfirm, liqpay.ActionCVV:
+	default:
+		if stringValue(payload["order_id"]) == "" {
+			return nil, &scriptedError{code: string(liqpay.NonFinancialOrderIDEmpty), desc: "order_id is empty"}
+		}
+	}
+
+	return payload, nil
+}
+
+// popError returns the next scripted error for the action, if any. It must be called with mu held.
+func (s *Server) popError(action liqpay.Action) *scriptedError {
+	queue := s.errors[action]
+	if len(queue) == 0 {
+		return nil
+	}
+	s.errors[action] = queue[1:]
+	return &queue[0]
+}
+
+// newPaymentID returns a new unique payment ID. It must be called with mu held.
+func (s *Server) newPaymentID() int64 {
+	s.nextPaymentID++
+	return s.nextPaymentID
+}
+
+func (s *Server) handleCheckout(w http.ResponseWriter, r *http.Request) {
+	payload, decodeErr := s.decodeRequest(r)
+	if decodeErr != nil {
+		http.Error(w, decodeErr.desc, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; !ok {
+		s.orders[orderID] = &Order{
+			OrderID:     orderID,
+			PaymentID:   s.newPaymentID(),
+			Action:      liqpay.Action(stringValue(payload["action"])),
+			Amount:      amountValue(payload["amount"]),
+			Currency:    liqpay.Currency(stringValue(payload["currency"])),
+			Description: stringValue(payload["description"]),
+			ServerURL:   stringValue(payload["server_url"]),
+			CreateDate:  time.Now(),
+		}
+	}
+
+	http.Redirect(w, r, s.URL+"/checkout/"+url.PathEscape(orderID), http.StatusFound)
+}
+
+// requestKeys lists the parameters each server-server action accepts besides action, version and public_key.
+var requestKeys = map[liqpay.Action][]string{
+	liqpay.ActionStatus:              {"order_id"},
+	liqpay.ActionRefund:              {"order_id", "amount"},
+	liqpay.ActionHoldCompletion:      {"order_id", "amount"},
+	liqpay.ActionInvoiceSend:         {"order_id", "amount", "currency", "description", "email", "phone", "action_payment", "expired_date", "goods", "language", "result_url", "server_url"},
+	liqpay.ActionInvoiceCancel:       {"order_id"},
+	liqpay.ActionSubscribeUpdate:     {"order_id", "amount", "currency", "description"},
+	liqpay.ActionUnsubscribe:         {"order_id"},
+	liqpay.ActionPayToken:            {"order_id", "amount", "currency", "description", "card_token", "ip", "phone", "server_url"},
+	liqpay.ActionPaySplit:            {"order_id", "amount", "currency", "description", "card", "card_cvv", "card_exp_month", "card_exp_year", "ip", "phone", "language", "server_url", "split_rules"},
+	liqpay.ActionPay:                 {"order_id", "amount", "currency", "description", "card", "card_cvv", "card_exp_month", "card_exp_year", "ip", "phone", "language", "recurringbytoken", "result_url", "server_url", "dcc", "paytype"},
+	liqpay.ActionPayQR:               {"order_id", "amount", "currency", "description", "expired_date", "language", "server_url"},
+	liqpay.ActionPayCash:             {"order_id", "amount", "currency", "description", "phone", "expired_date", "language", "server_url"},
+	liqpay.ActionConfirm:             {"token", "confirm_code", "dcc"},
+	liqpay.ActionCVV:                 {"token", "cvv"},
+	liqpay.ActionReports:             {"date_from", "date_to", "resp_format"},
+	liqpay.ActionReportsCompensation: {"date", "resp_format"},
+}
+
+// checkRequestKeys rejects a request with a parameter its action does not accept, so a misspelled
+// parameter fails in tests instead of being silently ignored.
+func checkRequestKeys(payload map[string]any) *scriptedError {
+	keys, ok := requestKeys[liqpay.Action(stringValue(payload["action"]))]
+	if !ok {
+		return nil
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(payload)) {
+		switch {
+		case key == "action", key == "version", key == "public_key":
+		case !slices.Contains(keys, key):
+			return &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "unknown parameter " + key}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleServerRequest(w http.ResponseWriter, r *http.Request) {
+	payload, decodeErr := s.decodeRequest(r)
+	if decodeErr != nil {
+		writeError(w, decodeErr)
+		return
+	}
+	if keyErr := checkRequestKeys(payload); keyErr != nil {
+		writeError(w, keyErr)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	action := liqpay.Action(stringValue(payload["action"]))
+	if scripted := s.popError(action); scripted != nil {
+		writeError(w, scripted)
+		return
+	}
+
+	var (
+		res map[string]any
+		err *scriptedError
+	)
+
+	switch action {
+	case liqpay.ActionStatus:
+		res, err = s.status(payload)
+	case liqpay.ActionRefund:
+		res, err = s.refund(payload)
+	case liqpay.ActionHoldCompletion:
+		res, err = s.holdCompletion(payload)
+	case liqpay.ActionInvoiceSend:
+		res, err = s.invoiceSend(payload)
+	case liqpay.ActionInvoiceCancel:
+		res, err = s.invoiceCancel(payload)
+	case liqpay.ActionSubscribeUpdate:
+		res, err = s.subscribeUpdate(payload)
+	case liqpay.ActionUnsubscribe:
+		res, err = s.unsubscribe(payload)
+	case liqpay.ActionPayToken:
+		res, err = s.payToken(payload)
+	case liqpay.ActionPaySplit:
+		res, err = s.paySplit(payload)
+	case liqpay.ActionPay:
+		res, err = s.payCard(payload)
+	case liqpay.ActionPayQR:
+		res, err = s.payQR(payload)
+	case liqpay.ActionPayCash:
+		res, err = s.payCash(payload)
+	case liqpay.ActionConfirm:
+		if _, ok := payload["dcc"]; ok {
+			res, err = s.confirmDCC(payload)
+		} else {
+			res, err = s.confirm(payload, liqpay.StatusOTPVerify)
+		}
+	case liqpay.ActionCVV:
+		res, err = s.confirm(payload, liqpay.StatusCVVVerify)
+	case liqpay.ActionReports, liqpay.ActionReportsCompensation:
+		var rows []map[string]any
+		rows, err = s.reports(action, payload)
+		if err == nil && stringValue(payload["resp_format"]) == string(liqpay.ReportFormatCSV) {
+			writeCSV(w, rows)
+			return
+		}
+		res = map[string]any{"result": "success", "data": rows}
+	default:
+		err = &scriptedError{code: string(liqpay.NonFinancialAPIActionParameterMissing), desc: "unsupported action"}
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, res)
+}
+
+// order returns an existing payment for the request order_id. It must be called with mu held.
+func (s *Server) order(payload map[string]any) (*Order, *scriptedError) {
+	order, ok := s.orders[stringValue(payload["order_id"])]
+	if !ok || order.Status == "" {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentNotFound), desc: "payment not found"}
+	}
+	return order, nil
+}
+
+func (s *Server) status(payload map[string]any) (map[string]any, *scriptedError) {
+	order, err := s.order(payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.orderFields(order), nil
+}
+
+func (s *Server) refund(payload map[string]any) (map[string]any, *scriptedError) {
+	order, err := s.order(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	switch order.Status {
+	case liqpay.StatusHoldWait:
+		order.Status = liqpay.StatusReversed
+	case liqpay.StatusSuccess:
+		remaining := order.Amount.Sub(order.RefundedAmount)
+		amount := remaining
+		if v := amountValue(payload["amount"]); v.Sign() > 0 {
+			amount = v
+		}
+		if amount.Cmp(remaining) > 0 {
+			return nil, &scriptedError{code: string(liqpay.NonFinancialAmountHoldError), desc: "amount exceeds payment amount"}
+		}
+		order.RefundedAmount = order.RefundedAmount.Add(amount)
+		if order.RefundedAmount.Equal(order.Amount) {
+			order.Status = liqpay.StatusReversed
+		}
+	default:
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentStatusError), desc: "incorrect payment status"}
+	}
+	order.EndDate = time.Now()
+
+	return map[string]any{
+		"action":     liqpay.ActionRefund,
+		"payment_id": order.PaymentID,
+		"status":     liqpay.StatusReversed,
+		"result":     "ok",
+	}, nil
+}
+
+func (s *Server) holdCompletion(payload map[string]any) (map[string]any, *scriptedError) {
+	order, err := s.order(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != liqpay.StatusHoldWait {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentStatusError), desc: "incorrect payment status"}
+	}
+
+	if v := amountValue(payload["amount"]); v.Sign() > 0 {
+		if v.Cmp(order.Amount) > 0 {
+			return nil, &scriptedError{code: string(liqpay.NonFinancialAmountHoldError), desc: "amount exceeds payment amount"}
+		}
+		order.Amount = v
+	}
+	order.Action = liqpay.ActionHold
+	order.Status = liqpay.StatusSuccess
+	order.EndDate = time.Now()
+
+	return s.orderFields(order), nil
+}
+
+func (s *Server) invoiceSend(payload map[string]any) (map[string]any, *scriptedError) {
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; ok {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialDuplicateOrderID), desc: "order_id already exists"}
+	}
+
+	order := &Order{
+		OrderID:     orderID,
+		PaymentID:   s.newPaymentID(),
+		Action:      liqpay.ActionPay,
+		Status:      liqpay.StatusInvoiceWait,
+		Amount:      amountValue(payload["amount"]),
+		Currency:    liqpay.Currency(stringValue(payload["currency"])),
+		Description: stringValue(payload["description"]),
+		ServerURL:   stringValue(payload["server_url"]),
+		CreateDate:  time.Now(),
+	}
+	s.orders[orderID] = order
+
+	receiverType, receiverValue := "email", stringValue(payload["email"])
+	if receiverValue == "" {
+		receiverType, receiverValue = "phone", stringValue(payload["phone"])
+	}
+
+	return map[string]any{
+		"action":         liqpay.ActionPay,
+		"amount":         order.Amount,
+		"currency":       order.Currency,
+		"description":    order.Description,
+		"href":           s.URL + "/invoice/" + url.PathEscape(orderID),
+		"id":             order.PaymentID,
+		"order_id":       orderID,
+		"receiver_type":  receiverType,
+		"receiver_value": receiverValue,
+		"status":         order.Status,
+		"result":         "ok",
+	}, nil
+}
+
+func (s *Server) invoiceCancel(payload map[string]any) (map[string]any, *scriptedError) {
+	order, err := s.order(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != liqpay.StatusInvoiceWait {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentStatusError), desc: "incorrect payment status"}
+	}
+	delete(s.orders, order.OrderID)
+
+	return map[string]any{
+		"invoice_id": order.PaymentID,
+		"result":     liqpay.CancelInvoiceResultOK,
+	}, nil
+}
+
+func (s *Server) subscribeUpdate(payload map[string]any) (map[string]any, *scriptedError) {
+	order, err := s.order(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != liqpay.StatusSubscribed {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentNotSubscribed), desc: "payment is not regular"}
+	}
+
+	if _, ok := payload["amount"]; ok {
+		order.Amount = amountValue(payload["amount"])
+	}
+	if v := stringValue(payload["currency"]); v != "" {
+		order.Currency = liqpay.Currency(v)
+	}
+	if v := stringValue(payload["description"]); v != "" {
+		order.Description = v
+	}
+	order.EndDate = time.Now()
+
+	return s.orderFields(order), nil
+}
+
+func (s *Server) unsubscribe(payload map[string]any) (map[string]any, *scriptedError) {
+	order, err := s.order(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != liqpay.StatusSubscribed {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentNotSubscribed), desc: "payment is not regular"}
+	}
+	order.Status = liqpay.StatusUnsubscribed
+	order.EndDate = time.Now()
+
+	return s.orderFields(order), nil
+}
+
+func (s *Server) payToken(payload map[string]any) (map[string]any, *scriptedError) {
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; ok {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialDuplicateOrderID), desc: "order_id already exists"}
+	}
+
+	token := stringValue(payload["card_token"])
+	if !s.cardTokens[token] {
+		return nil, &scriptedError{code: strconv.Itoa(int(liqpay.FinancialTokenNotFound)), desc: "token not found"}
+	}
+
+	order := &Order{
+		OrderID:     orderID,
+		PaymentID:   s.newPaymentID(),
+		Action:      liqpay.ActionPay,
+		Status:      liqpay.StatusSuccess,
+		Amount:      amountValue(payload["amount"]),
+		Currency:    liqpay.Currency(stringValue(payload["currency"])),
+		Description: stringValue(payload["description"]),
+		CardToken:   token,
+		ServerURL:   stringValue(payload["server_url"]),
+		CreateDate:  time.Now(),
+		EndDate:     time.Now(),
+	}
+	s.orders[orderID] = order
+
+	return s.orderFields(order), nil
+}
+
+func (s *Server) payCard(payload map[string]any) (map[string]any, *scriptedError) {
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; ok {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialDuplicateOrderID), desc: "order_id already exists"}
+	}
+
+	card := stringValue(payload["card"])
+	if card == "" {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialInvalidCardNumber), desc: "card is empty"}
+	}
+
+	order := &Order{
+		OrderID:     orderID,
+		PaymentID:   s.newPaymentID(),
+		Action:      liqpay.ActionPay,
+		Status:      liqpay.StatusSuccess,
+		Amount:      amountValue(payload["amount"]),
+		Currency:    liqpay.Currency(stringValue(payload["currency"])),
+		Description: stringValue(payload["description"]),
+		PayType:     liqpay.PayType(stringValue(payload["paytype"])),
+		ServerURL:   stringValue(payload["server_url"]),
+		CreateDate:  time.Now(),
+		EndDate:     time.Now(),
+	}
+	if step, ok := s.cardSteps[card]; ok {
+		order.Status = step
+		order.EndDate = time.Time{}
+	}
+	if offer, ok := s.cardDCC[card]; ok && stringValue(payload["dcc"]) == "Y" && offer.currency != order.Currency {
+		offer.next = order.Status
+		s.dccOffers[orderID] = offer
+		order.Status = liqpay.StatusDCCVerify
+		order.EndDate = time.Time{}
+	}
+	s.orders[orderID] = order
+
+	return s.cardPaymentFields(order), nil
+}
+
+// cardPaymentFields returns the fields of a server-server card payment, including the data
+// for the next payment step. It must be called with mu held.
+func (s *Server) cardPaymentFields(order *Order) map[string]any {
+	fields := s.orderFields(order)
+	switch order.Status {
+	case liqpay.Status3DSVerify:
+		fields["redirect_to"] = s.URL + "/3ds/" + url.PathEscape(order.OrderID)
+	case liqpay.StatusOTPVerify, liqpay.StatusCVVVerify, liqpay.StatusDCCVerify:
+		token := fmt.Sprintf("confirm-%d", order.PaymentID)
+		s.confirmTokens[token] = order.OrderID
+		fields["token"] = token
+	}
+	if offer, ok := s.dccOffers[order.OrderID]; ok && order.Status == liqpay.StatusDCCVerify {
+		fields["dcc_currency"] = offer.currency
+		fields["dcc_rate"] = offer.rate
+		fields["dcc_amount"] = order.Amount.Convert(offer.rate, offer.currency)
+	}
+	return fields
+}
+
+func (s *Server) payQR(payload map[string]any) (map[string]any, *scriptedError) {
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; ok {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialDuplicateOrderID), desc: "order_id already exists"}
+	}
+
+	order := &Order{
+		OrderID:     orderID,
+		PaymentID:   s.newPaymentID(),
+		Action:      liqpay.ActionPay,
+		Status:      liqpay.StatusWaitQR,
+		Amount:      amountValue(payload["amount"]),
+		Currency:    liqpay.Currency(stringValue(payload["currency"])),
+		Description: stringValue(payload["description"]),
+		PayType:     liqpay.PayTypeQRCodeScanning,
+		ServerURL:   stringValue(payload["server_url"]),
+		CreateDate:  time.Now(),
+	}
+	s.orders[orderID] = order
+
+	fields := s.orderFields(order)
+	fields["qr_code"] = s.URL + "/qr/" + url.PathEscape(orderID)
+
+	return fields, nil
+}
+
+func (s *Server) payCash(payload map[string]any) (map[string]any, *scriptedError) {
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; ok {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialDuplicateOrderID), desc: "order_id already exists"}
+	}
+
+	order := &Order{
+		OrderID:     orderID,
+		PaymentID:   s.newPaymentID(),
+		Action:      liqpay.ActionPay,
+		Status:      liqpay.StatusCashWait,
+		Amount:      amountValue(payload["amount"]),
+		Currency:    liqpay.Currency(stringValue(payload["currency"])),
+		Description: stringValue(payload["description"]),
+		PayType:     liqpay.PayTypeCash,
+		ServerURL:   stringValue(payload["server_url"]),
+		CreateDate:  time.Now(),
+	}
+	if order.Currency != liqpay.CurrencyUAH {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialCashPaymentAcquirerNotAllowed), desc: "cash payments are accepted in UAH only"}
+	}
+	s.orders[orderID] = order
+
+	expiredDate := order.CreateDate.Add(24 * time.Hour)
+	if v := stringValue(payload["expired_date"]); v != "" {
+		if t, err := time.Parse(liqpay.TimeLayout, v); err == nil {
+			expiredDate = t
+		}
+	}
+
+	fields := s.orderFields(order)
+	fields["payment_code"] = fmt.Sprintf("%010d", order.PaymentID)
+	fields["expired_date"] = expiredDate.UTC().Format(liqpay.TimeLayout)
+
+	return fields, nil
+}
+
+// confirm completes a card payment awaiting the OTP or CVV confirmation. It must be called with mu held.
+func (s *Server) confirm(payload map[string]any, status liqpay.Status) (map[string]any, *scriptedError) {
+	token := stringValue(payload["token"])
+	order, ok := s.orders[s.confirmTokens[token]]
+	if !ok || order.Status != status {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentNotFound), desc: "payment not found"}
+	}
+
+	code := payload["confirm_code"]
+	if status == liqpay.StatusCVVVerify {
+		code = payload["cvv"]
+	}
+	if stringValue(code) == "" {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialParameterEmpty), desc: "confirmation code is empty"}
+	}
+
+	delete(s.confirmTokens, token)
+	order.Status = liqpay.StatusSuccess
+	order.EndDate = time.Now()
+
+	return s.orderFields(order), nil
+}
+
+// confirmDCC applies the customer's choice for the DCC offer and continues the card payment.
+// It must be called with mu held.
+func (s *Server) confirmDCC(payload map[string]any) (map[string]any, *scriptedError) {
+	token := stringValue(payload["token"])
+	order, ok := s.orders[s.confirmTokens[token]]
+	if !ok || order.Status != liqpay.StatusDCCVerify {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialPaymentNotFound), desc: "payment not found"}
+	}
+
+	offer := s.dccOffers[order.OrderID]
+	switch stringValue(payload["dcc"]) {
+	case "Y":
+		order.DebitAmount = order.Amount.Convert(offer.rate, offer.currency)
+		order.DebitCurrency = offer.currency
+	case "N":
+	default:
+		return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "dcc must be Y or N"}
+	}
+
+	delete(s.confirmTokens, token)
+	delete(s.dccOffers, order.OrderID)
+	order.Status = offer.next
+	if order.Status.IsFinal() {
+		order.EndDate = time.Now()
+	}
+
+	return s.cardPaymentFields(order), nil
+}
+
+func (s *Server) paySplit(payload map[string]any) (map[string]any, *scriptedError) {
+	orderID := stringValue(payload["order_id"])
+	if _, ok := s.orders[orderID]; ok {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialDuplicateOrderID), desc: "order_id already exists"}
+	}
+
+	rules, _ := payload["split_rules"].([]any)
+	var total liqpay.Amount
+	for _, rule := range rules {
+		if rule, ok := rule.(map[string]any); ok {
+			total = total.Add(amountValue(rule["amount"]))
+		}
+	}
+
+	amount := amountValue(payload["amount"])
+	if !total.Equal(amount) {
+		return nil, &scriptedError{code: string(liqpay.NonFinancialSplitAmountMismatch), desc: "split amounts do not match payment amount"}
+	}
+
+	order := &Order{
+		OrderID:     orderID,
+		PaymentID:   s.newPaymentID(),
+		Action:      liqpay.ActionPaySplit,
+		Status:      liqpay.StatusSuccess,
+		Amount:      amount,
+		Currency:    liqpay.Currency(stringValue(payload["currency"])),
+		Description: stringValue(payload["description"]),
+		ServerURL:   stringValue(payload["server_url"]),
+		CreateDate:  time.Now(),
+		EndDate:     time.Now(),
+	}
+	s.orders[orderID] = order
+
+	return s.orderFields(order), nil
+}
+
+// orderFields returns the order as a LiqPay status response or callback payload. It must be called with mu held.
+func (s *Server) orderFields(order *Order) map[string]any {
+	// The fake charges a 1.5% receiver commission.
+	minor, _ := order.Amount.Round(order.Currency.MinorUnits()).MinorUnits(order.Currency)
+	commission := liqpay.AmountFromMinor((minor*15+500)/1000, order.Currency)
+
+	payType := order.PayType
+	if payType == "" {
+		payType = liqpay.PayTypeCard
+	}
+
+	amountDebit, currencyDebit := order.Amount, order.Currency
+	if order.DebitCurrency != "" {
+		amountDebit, currencyDebit = order.DebitAmount, order.DebitCurrency
+	}
+
+	fields := map[string]any{
+		"action":              order.Action,
+		"payment_id":          order.PaymentID,
+		"status":              order.Status,
+		"version":             3,
+		"type":                "buy",
+		"paytype":             payType,
+		"public_key":          s.PublicKey,
+		"acq_id":              414963,
+		"order_id":            order.OrderID,
+		"liqpay_order_id":     fmt.Sprintf("FAKE%d", order.PaymentID),
+		"description":         order.Description,
+		"sender_card_mask2":   "424242*42",
+		"sender_card_bank":    "Test",
+		"sender_card_type":    "visa",
+		"sender_card_country": 804,
+		"amount":              order.Amount,
+		"currency":            order.Currency,
+		"sender_commission":   0.0,
+		"receiver_commission": commission,
+		"agent_commission":    0.0,
+		"amount_debit":        amountDebit,
+		"amount_credit":       order.Amount,
+		"commission_debit":    0.0,
+		"commission_credit":   commission,
+		"currency_debit":      currencyDebit,
+		"currency_credit":     order.Currency,
+		"mpi_eci":             7,
+		"is_3ds":              false,
+		"create_date":         order.CreateDate.UnixMilli(),
+		"transaction_id":      order.PaymentID,
+	}
+
+	if !order.EndDate.IsZero() {
+		fields["end_date"] = order.EndDate.UnixMilli()
+	}
+	if order.CardToken != "" {
+		fields["card_token"] = order.CardToken
+	}
+	if !order.RefundedAmount.IsZero() {
+		fields["refund_amount"] = order.RefundedAmount
+	}
+	if order.ErrCode != "" {
+		fields["err_code"] = order.ErrCode
+		fields["err_description"] = order.ErrCode
+	}
+
+	return fields
+}
+
+// reports returns the payments archive rows for the date_from..date_to period, or the compensation register rows
+// of successful payments completed on the date. It must be called with mu held.
+func (s *Server) reports(action liqpay.Action, payload map[string]any) ([]map[string]any, *scriptedError) {
+	var include func(order *Order) bool
+	switch action {
+	case liqpay.ActionReports:
+		from, fromErr := strconv.ParseInt(stringValue(payload["date_from"]), 10, 64)
+		to, toErr := strconv.ParseInt(stringValue(payload["date_to"]), 10, 64)
+		if fromErr != nil || toErr != nil {
+			return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "invalid date_from or date_to"}
+		}
+		include = func(order *Order) bool {
+			created := order.CreateDate.UnixMilli()
+			return created >= from && created <= to
+		}
+	default:
+		date, dateErr := time.Parse(time.DateOnly, stringValue(payload["date"]))
+		if dateErr != nil {
+			return nil, &scriptedError{code: string(liqpay.NonFinancialParameterIncorrect), desc: "invalid date"}
+		}
+		include = func(order *Order) bool {
+			return order.Status == liqpay.StatusSuccess && order.EndDate.UTC().Format(time.DateOnly) == date.Format(time.DateOnly)
+		}
+	}
+
+	rows := []map[string]any{}
+	for _, order := range s.orders {
+		if order.Status == "" || !include(order) {
+			continue
+		}
+
+		row := s.orderFields(order)
+		if action == liqpay.ActionReportsCompensation {
+			row["compensation_id"] = order.PaymentID
+			row["compensation_date"] = order.EndDate.UTC().Format(liqpay.TimeLayout)
+		}
+		rows = append(rows, row)
+	}
+
+	slices.SortFunc(rows, func(a, b map[string]any) int {
+		return cmp.Compare(a["payment_id"].(int64), b["payment_id"].(int64))
+	})
+
+	return rows, nil
+}
+
+// rewriteTransport routes every request to the target URL.
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+
+	return t.base.RoundTrip(req)
+}
+
+func writeError(w http.ResponseWriter, err *scriptedError) {
+	res := map[string]any{
+		"result":          "error",
+		"status":          liqpay.StatusError,
+		"err_code":        err.code,
+		"err_description": err.desc,
+	}
+	if _, convErr := strconv.Atoi(err.code); convErr == nil {
+		res["status"] = liqpay.StatusFailure
+		res["err_erc"] = err.code
+	}
+
+	writeJSON(w, res)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeCSV(w http.ResponseWriter, rows []map[string]any) {
+	var header []string
+	for _, row := range rows {
+		for key := range row {
+			if !slices.Contains(header, key) {
+				header = append(header, key)
+			}
+		}
+	}
+	slices.Sort(header)
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	_ = cw.Write(header)
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, key := range header {
+			if v, ok := row[key]; ok {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+
+	w.Header().Set("Content-Type", "text/csv")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func stringValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+func amountValue(v any) liqpay.Amount {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	}
+
+	amount, _ := liqpay.ParseAmount(s)
+	return amount
+}
@@ -0,0 +1,482 @@
+package liqpaytest_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kabachoksolutions/liqpay"
+	"github.com/kabachoksolutions/liqpay/liqpaytest"
+)
+
+func TestClientRoundTrip(t *testing.T) {
+	amount := liqpay.MustParseAmount("100")
+
+	cardPayment := func(card, dcc string) *liqpay.CardPaymentRequest {
+		return &liqpay.CardPaymentRequest{
+			Amount:       amount,
+			Card:         card,
+			CardCVV:      "123",
+			CardExpMonth: "12",
+			CardExpYear:  "30",
+			Currency:     liqpay.CurrencyUAH,
+			Description:  "Test",
+			IP:           "127.0.0.1",
+			OrderID:      "order-1",
+			DCC:          dcc,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		algorithm liqpay.SignatureAlgorithm
+		run       func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client)
+	}{
+		{
+			name: "checkout",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				redirect, err := c.CreateCheckout(&liqpay.CheckoutRequest{Amount: amount, Currency: liqpay.CurrencyUAH, Description: "Test", OrderID: "order-1"})
+				if err != nil {
+					t.Fatalf("CreateCheckout() error = %v", err)
+				}
+				if !strings.HasSuffix(redirect, "/checkout/order-1") {
+					t.Errorf("CreateCheckout() = %q, want the checkout page of order-1", redirect)
+				}
+
+				if _, err := c.Status("order-1"); !errors.Is(err, liqpay.ErrPaymentNotFound) {
+					t.Fatalf("Status() before payment error = %v, want %v", err, liqpay.ErrPaymentNotFound)
+				}
+
+				mustComplete(t, srv, "order-1")
+				wantStatus(t, c, "order-1", liqpay.StatusSuccess)
+			},
+		},
+		{
+			name:      "checkout signed with sha3-256",
+			algorithm: liqpay.SignatureAlgorithmSHA3256,
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				if _, err := c.CreateCheckout(&liqpay.CheckoutRequest{Amount: amount, Currency: liqpay.CurrencyUAH, Description: "Test", OrderID: "order-1"}); err != nil {
+					t.Fatalf("CreateCheckout() error = %v", err)
+				}
+				mustComplete(t, srv, "order-1")
+				wantStatus(t, c, "order-1", liqpay.StatusSuccess)
+			},
+		},
+		{
+			name: "status",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				srv.AddOrder(liqpaytest.Order{OrderID: "order-1", Action: liqpay.ActionPay, Status: liqpay.StatusSuccess, Amount: amount, Currency: liqpay.CurrencyUAH})
+
+				v := wantStatus(t, c, "order-1", liqpay.StatusSuccess)
+				if !v.Amount.Equal(amount) || v.Currency != liqpay.CurrencyUAH {
+					t.Errorf("Status() = %s %s, want %s %s", v.Amount, v.Currency, amount, liqpay.CurrencyUAH)
+				}
+
+				if _, err := c.Status("order-2"); !errors.Is(err, liqpay.ErrPaymentNotFound) {
+					t.Errorf("Status() of an unknown order error = %v, want %v", err, liqpay.ErrPaymentNotFound)
+				}
+			},
+		},
+		{
+			name: "refund",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				srv.AddOrder(liqpaytest.Order{OrderID: "order-1", Action: liqpay.ActionPay, Status: liqpay.StatusSuccess, Amount: amount, Currency: liqpay.CurrencyUAH})
+
+				v, err := c.Refund("order-1", liqpay.MustParseAmount("40"))
+				if err != nil {
+					t.Fatalf("Refund() error = %v", err)
+				}
+				if v.Status != liqpay.StatusReversed {
+					t.Errorf("Refund() status = %s, want %s", v.Status, liqpay.StatusReversed)
+				}
+
+				order, _ := srv.Order("order-1")
+				if want := liqpay.MustParseAmount("40"); !order.RefundedAmount.Equal(want) {
+					t.Errorf("refunded amount = %s, want %s", order.RefundedAmount, want)
+				}
+
+				if _, err := c.Refund("order-1", liqpay.MustParseAmount("100")); !liqpay.ErrorRefersToAPI(err) {
+					t.Errorf("Refund() over the payment amount error = %v, want an APIError", err)
+				}
+			},
+		},
+		{
+			name: "hold completion",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				if _, err := c.CreateHoldCheckout(&liqpay.CheckoutRequest{Amount: amount, Currency: liqpay.CurrencyUAH, Description: "Test", OrderID: "order-1"}); err != nil {
+					t.Fatalf("CreateHoldCheckout() error = %v", err)
+				}
+				mustComplete(t, srv, "order-1")
+				wantStatus(t, c, "order-1", liqpay.StatusHoldWait)
+
+				v, err := c.CompleteHold("order-1", liqpay.MustParseAmount("60"))
+				if err != nil {
+					t.Fatalf("CompleteHold() error = %v", err)
+				}
+				if v.Status != liqpay.StatusSuccess {
+					t.Errorf("CompleteHold() status = %s, want %s", v.Status, liqpay.StatusSuccess)
+				}
+			},
+		},
+		{
+			name: "hold cancellation",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				if _, err := c.CreateHoldCheckout(&liqpay.CheckoutRequest{Amount: amount, Currency: liqpay.CurrencyUAH, Description: "Test", OrderID: "order-1"}); err != nil {
+					t.Fatalf("CreateHoldCheckout() error = %v", err)
+				}
+				mustComplete(t, srv, "order-1")
+
+				if _, err := c.CancelHold("order-1"); err != nil {
+					t.Fatalf("CancelHold() error = %v", err)
+				}
+				wantStatus(t, c, "order-1", liqpay.StatusReversed)
+			},
+		},
+		{
+			name: "token",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				srv.AddCardToken("token-1")
+
+				req := &liqpay.TokenPaymentRequest{Amount: amount, CardToken: "token-1", Currency: liqpay.CurrencyUAH, Description: "Test", IP: "127.0.0.1", OrderID: "order-1"}
+				v, err := c.PayWithToken(req)
+				if err != nil {
+					t.Fatalf("PayWithToken() error = %v", err)
+				}
+				if v.Status != liqpay.StatusSuccess {
+					t.Errorf("PayWithToken() status = %s, want %s", v.Status, liqpay.StatusSuccess)
+				}
+
+				req.CardToken, req.OrderID = "token-2", "order-2"
+				var tokenErr *liqpay.TokenError
+				if _, err := c.PayWithToken(req); !errors.As(err, &tokenErr) {
+					t.Fatalf("PayWithToken() with an unknown token error = %v, want TokenError", err)
+				}
+				if !tokenErr.RequiresNewCard() {
+					t.Errorf("TokenError(%d).RequiresNewCard() = false, want true", tokenErr.Financial())
+				}
+			},
+		},
+		{
+			name: "otp",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				srv.SetCardVerification("4242424242424242", liqpay.StatusOTPVerify)
+
+				step := mustPayByCard(t, c, cardPayment("4242424242424242", ""), liqpay.PaymentStepOTP)
+				step, err := c.ConfirmOTP(step.Token, "123456")
+				if err != nil {
+					t.Fatalf("ConfirmOTP() error = %v", err)
+				}
+				wantDone(t, step, liqpay.StatusSuccess)
+			},
+		},
+		{
+			name: "cvv",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				srv.SetCardVerification("4242424242424242", liqpay.StatusCVVVerify)
+
+				step := mustPayByCard(t, c, cardPayment("4242424242424242", ""), liqpay.PaymentStepCVV)
+				step, err := c.ConfirmCVV(step.Token, "123")
+				if err != nil {
+					t.Fatalf("ConfirmCVV() error = %v", err)
+				}
+				wantDone(t, step, liqpay.StatusSuccess)
+			},
+		},
+		{
+			name: "dcc",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				srv.SetCardDCC("4242424242424242", liqpay.CurrencyUSD, liqpay.MustParseAmount("0.0245"))
+
+				step := mustPayByCard(t, c, cardPayment("4242424242424242", "Y"), liqpay.PaymentStepDCC)
+				if want := "100.00 UAH = 2.45 USD (1 UAH = 0.0245 USD)"; step.DCC == nil || step.DCC.String() != want {
+					t.Fatalf("PayByCard() offer = %v, want %s", step.DCC, want)
+				}
+
+				step, err := c.ConfirmDCC(step.Token, true)
+				if err != nil {
+					t.Fatalf("ConfirmDCC() error = %v", err)
+				}
+				wantDone(t, step, liqpay.StatusSuccess)
+
+				order, _ := srv.Order("order-1")
+				if want := liqpay.MustParseAmount("2.45"); !order.DebitAmount.Equal(want) || order.DebitCurrency != liqpay.CurrencyUSD {
+					t.Errorf("debited = %s %s, want %s %s", order.DebitAmount, order.DebitCurrency, want, liqpay.CurrencyUSD)
+				}
+			},
+		},
+		{
+			name: "cash",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				v, err := c.CreateCashPayment(&liqpay.CashPaymentRequest{Amount: amount, Currency: liqpay.CurrencyUAH, Description: "Test", OrderID: "order-1"})
+				if err != nil {
+					t.Fatalf("CreateCashPayment() error = %v", err)
+				}
+				if v.Status != liqpay.StatusCashWait || v.PaymentCode == "" {
+					t.Errorf("CreateCashPayment() = %s %q, want %s with a payment code", v.Status, v.PaymentCode, liqpay.StatusCashWait)
+				}
+
+				mustComplete(t, srv, "order-1")
+				wantStatus(t, c, "order-1", liqpay.StatusSuccess)
+			},
+		},
+		{
+			name: "qr",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				v, err := c.CreateQRPayment(&liqpay.QRPaymentRequest{Amount: amount, Currency: liqpay.CurrencyUAH, Description: "Test", OrderID: "order-1"})
+				if err != nil {
+					t.Fatalf("CreateQRPayment() error = %v", err)
+				}
+				if v.Status != liqpay.StatusWaitQR || v.QRCode == "" {
+					t.Errorf("CreateQRPayment() = %s %q, want %s with a QR code", v.Status, v.QRCode, liqpay.StatusWaitQR)
+				}
+
+				mustComplete(t, srv, "order-1")
+				wantStatus(t, c, "order-1", liqpay.StatusSuccess)
+			},
+		},
+		{
+			name: "reports",
+			run: func(t *testing.T, srv *liqpaytest.Server, c liqpay.Client) {
+				now := time.Now()
+				srv.AddOrder(liqpaytest.Order{OrderID: "order-1", Action: liqpay.ActionPay, Status: liqpay.StatusSuccess, Amount: amount, Currency: liqpay.CurrencyUAH, CreateDate: now, EndDate: now})
+				srv.AddOrder(liqpaytest.Order{OrderID: "order-2", Action: liqpay.ActionPay, Status: liqpay.StatusSuccess, Amount: amount, Currency: liqpay.CurrencyUAH, CreateDate: now.Add(-48 * time.Hour), EndDate: now.Add(-48 * time.Hour)})
+
+				payments, err := c.Reports(now.Add(-time.Hour), now.Add(time.Hour))
+				if err != nil {
+					t.Fatalf("Reports() error = %v", err)
+				}
+				if len(payments.Data) != 1 || payments.Data[0].OrderID != "order-1" {
+					t.Errorf("Reports() = %+v, want order-1 only", payments.Data)
+				}
+
+				csv, err := c.ReportsCSV(now.Add(-time.Hour), now.Add(time.Hour))
+				if err != nil {
+					t.Fatalf("ReportsCSV() error = %v", err)
+				}
+				if !strings.Contains(string(csv), "order-1") || strings.Contains(string(csv), "order-2") {
+					t.Errorf("ReportsCSV() = %q, want order-1 only", csv)
+				}
+
+				compensations, err := c.ReportsCompensation(now)
+				if err != nil {
+					t.Fatalf("ReportsCompensation() error = %v", err)
+				}
+				if len(compensations.Data) != 1 || compensations.Data[0].OrderID != "order-1" {
+					t.Errorf("ReportsCompensation() = %+v, want order-1 only", compensations.Data)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := liqpaytest.NewServer("public", "private")
+			defer srv.Close()
+			srv.SignatureAlgorithm = tt.algorithm
+
+			tt.run(t, srv, liqpay.NewClient(srv.Config(), nil))
+		})
+	}
+}
+
+func TestServerRejectsRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		privateKey string
+		payload    map[string]any
+		wantCode   liqpay.NonFinancialError
+	}{
+		{
+			name:       "invalid signature",
+			privateKey: "other",
+			payload:    map[string]any{"action": "status", "order_id": "order-1"},
+			wantCode:   liqpay.NonFinancialInvalidSignature,
+		},
+		{
+			name:     "unknown parameter",
+			payload:  map[string]any{"action": "status", "order_id": "order-1", "amount": 1},
+			wantCode: liqpay.NonFinancialParameterIncorrect,
+		},
+		{
+			name:     "otp sent as otp",
+			payload:  map[string]any{"action": "confirm", "token": "token-1", "otp": "123456"},
+			wantCode: liqpay.NonFinancialParameterIncorrect,
+		},
+		{
+			name:     "empty order_id",
+			payload:  map[string]any{"action": "status"},
+			wantCode: liqpay.NonFinancialOrderIDEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := liqpaytest.NewServer("public", "private")
+			defer srv.Close()
+
+			privateKey := srv.PrivateKey
+			if tt.privateKey != "" {
+				privateKey = tt.privateKey
+			}
+
+			payload := map[string]any{"version": liqpay.CurrentAPIVersion, "public_key": srv.PublicKey}
+			for k, v := range tt.payload {
+				payload[k] = v
+			}
+			encoded, err := json.Marshal(payload)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			data := base64.StdEncoding.EncodeToString(encoded)
+			sum := sha1.Sum([]byte(privateKey + data + privateKey))
+
+			resp, err := http.PostForm(srv.URL+"/api/request", url.Values{
+				"data":      {data},
+				"signature": {base64.StdEncoding.EncodeToString(sum[:])},
+			})
+			if err != nil {
+				t.Fatalf("PostForm() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			var body struct {
+				Result  string `json:"result"`
+				ErrCode string `json:"err_code"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if body.Result != "error" || body.ErrCode != string(tt.wantCode) {
+				t.Errorf("response = %s %s, want error %s", body.Result, body.ErrCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func mustComplete(t *testing.T, srv *liqpaytest.Server, orderID string) {
+	t.Helper()
+
+	if err := srv.CompletePayment(orderID); err != nil {
+		t.Fatalf("CompletePayment() error = %v", err)
+	}
+}
+
+func mustPayByCard(t *testing.T, c liqpay.Client, req *liqpay.CardPaymentRequest, want liqpay.PaymentStep) *liqpay.PaymentStepResult {
+	t.Helper()
+
+	step, err := c.PayByCard(req)
+	if err != nil {
+		t.Fatalf("PayByCard() error = %v", err)
+	}
+	if step.Step != want || step.Token == "" {
+		t.Fatalf("PayByCard() step = %s with token %q, want %s with a token", step.Step, step.Token, want)
+	}
+	return step
+}
+
+func wantStatus(t *testing.T, c liqpay.Client, orderID string, want liqpay.Status) *liqpay.StatusResponse {
+	t.Helper()
+
+	v, err := c.Status(orderID)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if v.Status != want {
+		t.Errorf("Status() = %s, want %s", v.Status, want)
+	}
+	return v
+}
+
+func wantDone(t *testing.T, step *liqpay.PaymentStepResult, want liqpay.Status) {
+	t.Helper()
+
+	if step.Step != liqpay.PaymentStepDone || step.Response.Status != want {
+		t.Errorf("step = %s %s, want %s %s", step.Step, step.Response.Status, liqpay.PaymentStepDone, want)
+	}
+}
+
+func TestSendCallback(t *testing.T) {
+	srv := liqpaytest.NewServer("public", "private")
+	defer srv.Close()
+
+	var got *liqpay.Callback
+	handler := httptest.NewServer(liqpay.NewCallbackHandler(liqpay.NewClient(srv.Config(), nil), func(ctx context.Context, callback *liqpay.Callback) error {
+		got = callback
+		return nil
+	}))
+	defer handler.Close()
+
+	srv.AddOrder(liqpaytest.Order{
+		OrderID:   "order-1",
+		Action:    liqpay.ActionPay,
+		Status:    liqpay.StatusSuccess,
+		Amount:    liqpay.MustParseAmount("100"),
+		Currency:  liqpay.CurrencyUAH,
+		ServerURL: handler.URL,
+	})
+
+	if err := srv.SendCallback("order-1"); err != nil {
+		t.Fatalf("SendCallback() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("callback handler was not called")
+	}
+	if got.OrderID != "order-1" || got.Status != liqpay.StatusSuccess || got.MpiEci != 7 || got.SenderCardCountry != "804" {
+		t.Errorf("callback = %s %s %d %q, want order-1 success 7 \"804\"", got.OrderID, got.Status, got.MpiEci, got.SenderCardCountry)
+	}
+}
+
+func TestReportsCSV(t *testing.T) {
+	srv := liqpaytest.NewServer("public", "private")
+	defer srv.Close()
+
+	now := time.Now()
+	for _, orderID := range []string{"order-1", "order-2"} {
+		srv.AddOrder(liqpaytest.Order{OrderID: orderID, Action: liqpay.ActionPay, Status: liqpay.StatusSuccess, Amount: liqpay.MustParseAmount("100"), Currency: liqpay.CurrencyUAH, CreateDate: now, EndDate: now})
+	}
+	c := liqpay.NewClient(srv.Config(), nil)
+
+	tests := []struct {
+		name string
+		call func() ([]byte, error)
+	}{
+		{name: "reports", call: func() ([]byte, error) { return c.ReportsCSV(now.Add(-time.Hour), now.Add(time.Hour)) }},
+		{name: "compensation", call: func() ([]byte, error) { return c.ReportsCompensationCSV(now.UTC()) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.call()
+			if err != nil {
+				t.Fatalf("call error = %v", err)
+			}
+
+			records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+			if err != nil {
+				t.Fatalf("body is not csv: %v\n%s", err, body)
+			}
+			if len(records) != 3 {
+				t.Fatalf("records = %d, want a header and 2 rows:\n%s", len(records), body)
+			}
+
+			column := slices.Index(records[0], "order_id")
+			if column < 0 {
+				t.Fatalf("header = %v, want an order_id column", records[0])
+			}
+			if got := []string{records[1][column], records[2][column]}; !slices.Equal(got, []string{"order-1", "order-2"}) {
+				t.Errorf("order_id column = %v, want [order-1 order-2]", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package liqpay
+
+import (
+	"log/slog"
+	"os"
+)
+
+// redactedValue replaces values of sensitive fields in logs.
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are request and response fields that are never logged as is.
+var sensitiveFields = map[string]bool{
+	"card":              true,
+	"card_cvv":          true,
+	"card_exp_month":    true,
+	"card_exp_year":     true,
+	"card_token":        true,
+	"confirm_code":      true,
+	"cvv":               true,
+	"email":             true,
+	"payment_code":      true,
+	"phone":             true,
+	"sender_phone":      true,
+	"sender_card_mask2": true,
+	"sender_first_name": true,
+	"sender_last_name":  true,
+	"token":             true,
+}
+
+var (
+	// debugLogger is used when Config.Debug is set and Config.Logger is not.
+	debugLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	// discardLogger is used when logging is disabled.
+	discardLogger = slog.New(slog.DiscardHandler)
+)
+
+// redact returns a copy of the value with sensitive fields replaced, descending into nested objects and arrays.
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if sensitiveFields[key] {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = redact(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, value := range v {
+			redacted[i] = redact(value)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
@@ -0,0 +1,48 @@
+package liqpay
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want any
+	}{
+		{
+			name: "card payment confirmation token",
+			in:   map[string]any{"status": "otp_verify", "token": "confirm-1"},
+			want: map[string]any{"status": "otp_verify", "token": redactedValue},
+		},
+		{
+			name: "otp confirmation code",
+			in:   map[string]any{"action": "confirm", "token": "confirm-1", "confirm_code": "123456"},
+			want: map[string]any{"action": "confirm", "token": redactedValue, "confirm_code": redactedValue},
+		},
+		{
+			name: "cash payment code",
+			in:   map[string]any{"status": "cash_wait", "payment_code": "0001000001"},
+			want: map[string]any{"status": "cash_wait", "payment_code": redactedValue},
+		},
+		{
+			name: "nested card details",
+			in:   map[string]any{"data": []any{map[string]any{"card": "4242424242424242", "amount": 1.0}}},
+			want: map[string]any{"data": []any{map[string]any{"card": redactedValue, "amount": 1.0}}},
+		},
+		{
+			name: "scalar",
+			in:   "4242424242424242",
+			want: "4242424242424242",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redact(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
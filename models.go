@@ -1,23 +1,29 @@
 package liqpay
 
-import "time"
-
 type Action string
 
 const (
-	ActionPay             Action = "pay"              // Default payment
-	ActionHold            Action = "hold"             // Amount of hold on sender's account
-	ActionSubscribe       Action = "subscribe"        // Create subscription
-	ActionSubscribeUpdate Action = "subscribe_update" // Update subscription
-	ActionUnsubscribe     Action = "unsubscribe"      // Unsubscribe
-	ActionStatus          Action = "status"           // Payment status
-	ActionPayDonate       Action = "paydonate"        // Donation
-	ActionPaySplit        Action = "paysplit"         // Splitting payments
-	ActionAuth            Action = "auth"             // Card preauth
-	ActionRegular         Action = "regular"          // Regular payment
-	ActionRefund          Action = "refund"           // Refund payment
-	ActionInvoiceSend     Action = "invoice_send"     // Send invoice
-	ActionInvoiceCancel   Action = "invoice_cancel"   // Cancel invoice
+	ActionPay                 Action = "pay"                  // Default payment
+	ActionHold                Action = "hold"                 // Amount of hold on sender's account
+	ActionHoldCompletion      Action = "hold_completion"      // Completion of a two-stage payment
+	ActionSubscribe           Action = "subscribe"            // Create subscription
+	ActionSubscribeUpdate     Action = "subscribe_update"     // Update subscription
+	ActionUnsubscribe         Action = "unsubscribe"          // Unsubscribe
+	ActionStatus              Action = "status"               // Payment status
+	ActionPayDonate           Action = "paydonate"            // Donation
+	ActionPaySplit            Action = "paysplit"             // Splitting payments
+	ActionPayToken            Action = "paytoken"             // Payment by card token
+	ActionPayCash             Action = "paycash"              // Payment by cash at a self-service terminal
+	ActionPayQR               Action = "payqr"                // Payment by QR code
+	ActionConfirm             Action = "confirm"              // Confirmation of a payment by OTP
+	ActionCVV                 Action = "cvv"                  // Confirmation of a payment by CVV
+	ActionAuth                Action = "auth"                 // Card preauth
+	ActionRegular             Action = "regular"              // Regular payment
+	ActionRefund              Action = "refund"               // Refund payment
+	ActionInvoiceSend         Action = "invoice_send"         // Send invoice
+	ActionInvoiceCancel       Action = "invoice_cancel"       // Cancel invoice
+	ActionReports             Action = "reports"              // Payments archive
+	ActionReportsCompensation Action = "reports_compensation" // Compensation register
 )
 
 type Currency string
@@ -51,18 +57,57 @@ const (
 
 type Status string
 
+// Final statuses.
+const (
+	StatusError        Status = "error"        // Failed payment. Data is incorrect
+	StatusFailure      Status = "failure"      // Failed payment
+	StatusReversed     Status = "reversed"     // Payment refunded
+	StatusSubscribed   Status = "subscribed"   // Subscribed successfully
+	StatusSuccess      Status = "success"      // Successful payment
+	StatusUnsubscribed Status = "unsubscribed" // Unsubscribed successfully
+)
+
+// Statuses that require payment confirmation by the customer.
 const (
-	StatusError    Status = "error"    // Failed payment. Data is incorrect
-	StatusFailure  Status = "failure"  // Failed payment
-	StatusReversed Status = "reversed" // Payment refunded
-	StatusSuccess  Status = "success"  // Successful payment
+	Status3DSVerify       Status = "3ds_verify"       // 3DS verification of the customer is required
+	StatusCaptchaVerify   Status = "captcha_verify"   // Waiting for the customer to enter a captcha
+	StatusCVVVerify       Status = "cvv_verify"       // Sender's card CVV is required
+	StatusDCCVerify       Status = "dcc_verify"       // Waiting for the customer to accept or decline the dynamic currency conversion offer
+	StatusIVRVerify       Status = "ivr_verify"       // Waiting for the customer to confirm the payment by IVR call
+	StatusOTPVerify       Status = "otp_verify"       // OTP confirmation of the customer is required. OTP password is sent to the phone number
+	StatusPasswordVerify  Status = "password_verify"  // Waiting for the customer to enter the Privat24 password
+	StatusPhoneVerify     Status = "phone_verify"     // Waiting for the customer to enter the phone number
+	StatusPINVerify       Status = "pin_verify"       // Waiting for the customer to enter the card PIN
+	StatusReceiverVerify  Status = "receiver_verify"  // Receiver's data is required
+	StatusSenderVerify    Status = "sender_verify"    // Sender's data is required
+	StatusSenderAppVerify Status = "senderapp_verify" // Waiting for the customer to confirm the payment in the Privat24 application
+	StatusWaitQR          Status = "wait_qr"          // Waiting for the customer to scan the QR code
+	StatusWaitSender      Status = "wait_sender"      // Waiting for the customer to confirm the payment in the Privat24 / SENDER application
+	StatusP24Verify       Status = "p24_verify"       // Waiting for the customer to complete the payment in Privat24
+	StatusMPVerify        Status = "mp_verify"        // Waiting for the customer to complete the payment in MasterPass
+)
+
+// Other statuses.
+const (
+	StatusCashWait         Status = "cash_wait"         // Waiting for the customer to pay in cash at a self-service terminal
+	StatusHoldWait         Status = "hold_wait"         // Amount was held on the sender's account successfully
+	StatusInvoiceWait      Status = "invoice_wait"      // Invoice was created successfully, waiting for the payment
+	StatusPrepared         Status = "prepared"          // Payment was created, waiting for its completion by the sender
+	StatusProcessing       Status = "processing"        // Payment is being processed
+	StatusWaitAccept       Status = "wait_accept"       // Money was debited from the customer, but the shop has not been verified yet
+	StatusWaitCard         Status = "wait_card"         // Refund method is not set by the recipient
+	StatusWaitCompensation Status = "wait_compensation" // Payment is successful, it will be transferred in the daily compensation
+	StatusWaitLC           Status = "wait_lc"           // Letter of credit. Money was debited from the customer, waiting for the delivery confirmation
+	StatusWaitReserve      Status = "wait_reserve"      // Money is reserved for the refund
+	StatusWaitSecure       Status = "wait_secure"       // Payment is being verified
+	StatusTryAgain         Status = "try_again"         // Payment failed, the customer may try again
 )
 
 type Item struct {
 	Amount float64 `json:"amount"` // Quantity/volume
-	Cost   string  `json:"cost"`   // The cost of all units of the specified product in the receipt (number of units * unit cost)
+	Cost   Amount  `json:"cost"`   // The cost of all units of the specified product in the receipt (number of units * unit cost)
 	ID     string  `json:"id"`     // Item ID. You can get it in the Liqpay account - SCR - Kasa - Goods
-	Price  string  `json:"price"`  // Unit cost of goods
+	Price  Amount  `json:"price"`  // Unit cost of goods
 }
 
 type RROInfo struct {
@@ -72,18 +117,51 @@ type RROInfo struct {
 }
 
 type CheckoutRequest struct {
-	Action      Action    `json:"action"`                 // Transaction type
-	Amount      float64   `json:"amount"`                 // Payment amount
-	Currency    Currency  `json:"currency"`               // Payment currency
-	Description string    `json:"description"`            // Payment description
-	OrderID     string    `json:"order_id"`               // Unique purchase ID in your shop. Maximum length is 255 symbols
-	RROInfo     RROInfo   `json:"rro_info,omitempty"`     // Data for fiscalization
-	ExpiredDate string    `json:"expired_date,omitempty"` // Date and time until which customer is able to pay invoice by UTC. Should be sent in the following format 2016-04-24 00:00:00
-	Language    Language  `json:"language,omitempty"`     // Customer's language
-	PayTypes    []PayType `json:"pay_types,omitempty"`    // Parameter that gets the methods of payments that displayed on checkout. If the parameter is not passed, shop settings will be applied, Checkout tab
-	ResultURL   string    `json:"result_url,omitempty"`   // URL of your shop where the buyer would be redirected after completion of the purchase. Maximum length 510 symbols
-	ServerURL   string    `json:"server_url,omitempty"`   // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
-	VerifyCode  string    `json:"verifycode,omitempty"`   // Possible value Y. Dynamic verification code is generated and going back to Callback. Also generated code will be transferred to verification transactions for displaying in statement by client's card. Works for action = auth
+	Action      Action      `json:"action"`                // Transaction type
+	Amount      Amount      `json:"amount"`                // Payment amount
+	Currency    Currency    `json:"currency"`              // Payment currency
+	Description string      `json:"description"`           // Payment description
+	OrderID     string      `json:"order_id"`              // Unique purchase ID in your shop. Maximum length is 255 symbols
+	RROInfo     RROInfo     `json:"rro_info,omitempty"`    // Data for fiscalization
+	ExpiredDate Time        `json:"expired_date,omitzero"` // Date and time until which customer is able to pay invoice
+	Language    Language    `json:"language,omitempty"`    // Customer's language
+	PayTypes    []PayType   `json:"pay_types,omitempty"`   // Parameter that gets the methods of payments that displayed on checkout. If the parameter is not passed, shop settings will be applied, Checkout tab
+	ResultURL   string      `json:"result_url,omitempty"`  // URL of your shop where the buyer would be redirected after completion of the purchase. Maximum length 510 symbols
+	ServerURL   string      `json:"server_url,omitempty"`  // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
+	VerifyCode  string      `json:"verifycode,omitempty"`  // Possible value Y. Dynamic verification code is generated and going back to Callback. Also generated code will be transferred to verification transactions for displaying in statement by client's card. Works for action = auth
+	SplitRules  []SplitRule `json:"split_rules,omitempty"` // Rules for splitting the payment amount between several recipients. Amounts must sum up to the payment amount
+	DCC         string      `json:"dcc,omitempty"`         // Possible value Y. Customers with a foreign card are offered to pay in the card currency with dynamic currency conversion
+}
+
+type CommissionPayer string
+
+const (
+	CommissionPayerSender   CommissionPayer = "sender"   // Commission is paid by the sender
+	CommissionPayerReceiver CommissionPayer = "receiver" // Commission is paid by the receiver
+)
+
+type SplitRule struct {
+	PublicKey       string          `json:"public_key"`                 // Public key of the recipient shop
+	Amount          Amount          `json:"amount"`                     // Part of the payment amount transferred to the recipient
+	CommissionPayer CommissionPayer `json:"commission_payer,omitempty"` // Who pays the commission for this part: sender or receiver
+	ServerURL       string          `json:"server_url,omitempty"`       // URL API of the recipient for notifications of payment status change (server -> server)
+}
+
+type SplitPaymentRequest struct {
+	Action       Action      `json:"action"`               // Transaction type
+	Amount       Amount      `json:"amount"`               // Payment amount. For example: 5, 7.34
+	Card         string      `json:"card"`                 // Card number of the payer
+	CardCVV      string      `json:"card_cvv"`             // CVV/CVV2
+	CardExpMonth string      `json:"card_exp_month"`       // Expiry month of the payer's card. For example: 08
+	CardExpYear  string      `json:"card_exp_year"`        // Expiry year of the payer's card. For example: 19
+	Currency     Currency    `json:"currency"`             // Payment currency. Possible values: USD, EUR, UAH
+	Description  string      `json:"description"`          // Payment description
+	IP           string      `json:"ip"`                   // Client IP
+	OrderID      string      `json:"order_id"`             // Unique purchase ID in your shop. Maximum length is 255 symbols
+	SplitRules   []SplitRule `json:"split_rules"`          // Rules for splitting the payment amount between several recipients. Amounts must sum up to the payment amount
+	Phone        string      `json:"phone,omitempty"`      // Payer's mobile phone
+	Language     Language    `json:"language,omitempty"`   // Customer's language uk, en
+	ServerURL    string      `json:"server_url,omitempty"` // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
 }
 
 type StatusRequest struct {
@@ -92,59 +170,237 @@ type StatusRequest struct {
 }
 
 type StatusResponse struct {
-	AcqID              int      `json:"acq_id"`              // Acquirer ID
-	Action             Action   `json:"action"`              // Transaction type: pay, hold, paysplit, subscribe, paydonate, auth, regular
-	AgentCommission    float64  `json:"agent_commission"`    // Agent commission in payment currency
-	Amount             float64  `json:"amount"`              // Payment amount
-	AmountBonus        float64  `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
-	AmountCredit       float64  `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
-	AmountDebit        float64  `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
-	AuthCodeCredit     string   `json:"authcode_credit"`     // Authorization code for transaction of credit
-	AuthCodeDebit      string   `json:"authcode_debit"`      // Authorization code for transaction of debit
-	BonusProcent       float64  `json:"bonus_procent"`       // Discount rate in percent
-	BonusType          string   `json:"bonus_type"`          // Bonus type: bonusplus, discount_club, personal, promo
-	CardToken          string   `json:"card_token"`          // Sender's card token
-	CommissionCredit   float64  `json:"commission_credit"`   // Commission from the receiver in currency_credit
-	CommissionDebit    float64  `json:"commission_debit"`    // Commission from the sender in currency_debit
-	CreateDate         int64    `json:"create_date"`         // Date of payment creation
-	Currency           Currency `json:"currency"`            // Payment currency
-	CurrencyCredit     string   `json:"currency_credit"`     // Transaction currency of credit
-	CurrencyDebit      string   `json:"currency_debit"`      // Transaction currency of debit
-	Description        string   `json:"description"`         // Payment description
-	EndDate            int64    `json:"end_date"`            // Date of payment edition/end
-	Info               string   `json:"info"`                // Additional payment information
-	IP                 string   `json:"ip"`                  // Sender's IP address
-	Is3DS              bool     `json:"is_3ds"`              // True if transaction passed with 3DS, false otherwise
-	LiqpayOrderID      string   `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
-	MomentPart         string   `json:"moment_part"`         // Payment indication in parts
-	MPIECI             string   `json:"mpi_eci"`             // MPI ECI: 5 - transaction passed with 3DS, 6 - issuer of payer card doesn't support 3d Secure, 7 - operation passed without 3d Secure
-	OrderID            string   `json:"order_id"`            // Order_id payment
-	PaymentID          int      `json:"payment_id"`          // Payment id in LiqPay system
-	Paytype            string   `json:"paytype"`             // Method of payment: card, privat24, moment_part, cash, invoice, qr
-	PublicKey          string   `json:"public_key"`          // Shop public key
-	ReceiverCommission float64  `json:"receiver_commission"` // Receiver commission in payment currency
-	RRNCredit          string   `json:"rrn_credit"`          // Unique transaction ID in authorization and settlement system of issuer bank for credit
-	RRNDebit           string   `json:"rrn_debit"`           // Unique transaction ID in authorization and settlement system of issuer bank for debit
-	SenderBonus        float64  `json:"sender_bonus"`        // Sender's bonus in the payment currency
-	SenderCardBank     string   `json:"sender_card_bank"`    // Sender's card bank
-	SenderCardCountry  int      `json:"sender_card_country"` // Sender's card country (digital ISO 3166-1 code)
-	SenderCardMask2    string   `json:"sender_card_mask2"`   // Sender's card mask2
-	SenderCardType     string   `json:"sender_card_type"`    // Sender's card type (MC/Visa)
-	SenderCommission   float64  `json:"sender_commission"`   // Commission from the sender in the payment currency
-	SenderPhone        string   `json:"sender_phone"`        // Sender's phone number
-	Status             Status   `json:"status"`              // Payment status
+	AcqID              int        `json:"acq_id"`              // Acquirer ID
+	Action             Action     `json:"action"`              // Transaction type: pay, hold, paysplit, subscribe, paydonate, auth, regular
+	AgentCommission    Amount     `json:"agent_commission"`    // Agent commission in payment currency
+	Amount             Amount     `json:"amount"`              // Payment amount
+	AmountBonus        Amount     `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
+	AmountCredit       Amount     `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
+	AmountDebit        Amount     `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
+	AuthCodeCredit     jsonString `json:"authcode_credit"`     // Authorization code for transaction of credit
+	AuthCodeDebit      jsonString `json:"authcode_debit"`      // Authorization code for transaction of debit
+	BonusProcent       float64    `json:"bonus_procent"`       // Discount rate in percent
+	BonusType          string     `json:"bonus_type"`          // Bonus type: bonusplus, discount_club, personal, promo
+	CardToken          string     `json:"card_token"`          // Sender's card token
+	CommissionCredit   Amount     `json:"commission_credit"`   // Commission from the receiver in currency_credit
+	CommissionDebit    Amount     `json:"commission_debit"`    // Commission from the sender in currency_debit
+	CreateDate         Time       `json:"create_date"`         // Date of payment creation
+	Currency           Currency   `json:"currency"`            // Payment currency
+	CurrencyCredit     string     `json:"currency_credit"`     // Transaction currency of credit
+	CurrencyDebit      string     `json:"currency_debit"`      // Transaction currency of debit
+	Description        string     `json:"description"`         // Payment description
+	EndDate            Time       `json:"end_date"`            // Date of payment edition/end
+	Info               string     `json:"info"`                // Additional payment information
+	IP                 string     `json:"ip"`                  // Sender's IP address
+	Is3DS              bool       `json:"is_3ds"`              // True if transaction passed with 3DS, false otherwise
+	LiqpayOrderID      string     `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	MomentPart         string     `json:"moment_part"`         // Payment indication in parts
+	MPIECI             jsonString `json:"mpi_eci"`             // MPI ECI: 5 - transaction passed with 3DS, 6 - issuer of payer card doesn't support 3d Secure, 7 - operation passed without 3d Secure
+	OrderID            string     `json:"order_id"`            // Order_id payment
+	PaymentID          int        `json:"payment_id"`          // Payment id in LiqPay system
+	Paytype            string     `json:"paytype"`             // Method of payment: card, privat24, moment_part, cash, invoice, qr
+	PublicKey          string     `json:"public_key"`          // Shop public key
+	ReceiverCommission Amount     `json:"receiver_commission"` // Receiver commission in payment currency
+	RRNCredit          jsonString `json:"rrn_credit"`          // Unique transaction ID in authorization and settlement system of issuer bank for credit
+	RRNDebit           jsonString `json:"rrn_debit"`           // Unique transaction ID in authorization and settlement system of issuer bank for debit
+	SenderBonus        Amount     `json:"sender_bonus"`        // Sender's bonus in the payment currency
+	SenderCardBank     string     `json:"sender_card_bank"`    // Sender's card bank
+	SenderCardCountry  int        `json:"sender_card_country"` // Sender's card country (digital ISO 3166-1 code)
+	SenderCardMask2    string     `json:"sender_card_mask2"`   // Sender's card mask2
+	SenderCardType     string     `json:"sender_card_type"`    // Sender's card type (MC/Visa)
+	SenderCommission   Amount     `json:"sender_commission"`   // Commission from the sender in the payment currency
+	SenderPhone        string     `json:"sender_phone"`        // Sender's phone number
+	Status             Status     `json:"status"`              // Payment status
 }
 
 type RefundRequest struct {
 	Action  Action `json:"action"`   // Transaction type
-	Amount  string `json:"amount"`   // Payment amount. For example: 5, 7.34
+	Amount  Amount `json:"amount"`   // Payment amount. For example: 5, 7.34
 	OrderID string `json:"order_id"` // Unique purchase ID in your shop. Maximum length is 255 symbols
 }
 
 type RefundResponse struct {
 	Action    Action `json:"action"`     // Transaction type
 	PaymentID int64  `json:"payment_id"` // Payment id in LiqPay system
-	Status    string `json:"status"`     // Payment status
+	Status    Status `json:"status"`     // Payment status
+}
+
+type HoldCompletionRequest struct {
+	Action  Action `json:"action"`          // Transaction type
+	OrderID string `json:"order_id"`        // Unique purchase ID in your shop. Maximum length is 255 symbols
+	Amount  Amount `json:"amount,omitzero"` // Amount to capture. If not set, the whole held amount is captured
+}
+
+type HoldCancelRequest struct {
+	Action  Action `json:"action"`   // Transaction type
+	OrderID string `json:"order_id"` // Unique purchase ID in your shop. Maximum length is 255 symbols
+}
+
+type HoldResponse struct {
+	StatusResponse       // Payment state after the hold is completed or cancelled
+	TransactionID  int64 `json:"transaction_id"` // Id transactions in the LiqPay system
+	Version        int   `json:"version"`        // Version API
+}
+
+type TokenPaymentRequest struct {
+	Action      Action   `json:"action"`               // Transaction type
+	Amount      Amount   `json:"amount"`               // Payment amount. For example: 5, 7.34
+	CardToken   string   `json:"card_token"`           // Sender's card token
+	Currency    Currency `json:"currency"`             // Payment currency. Possible values: USD, EUR, UAH
+	Description string   `json:"description"`          // Payment description
+	IP          string   `json:"ip"`                   // Client IP
+	OrderID     string   `json:"order_id"`             // Unique purchase ID in your shop. Maximum length is 255 symbols
+	Phone       string   `json:"phone,omitempty"`      // Payer's mobile phone
+	ServerURL   string   `json:"server_url,omitempty"` // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
+}
+
+type TokenPaymentResponse struct {
+	StatusResponse       // Payment state
+	TransactionID  int64 `json:"transaction_id"` // Id transactions in the LiqPay system
+	Version        int   `json:"version"`        // Version API
+}
+
+type CardPaymentRequest struct {
+	Action           Action   `json:"action"`                     // Transaction type
+	Amount           Amount   `json:"amount"`                     // Payment amount. For example: 5, 7.34
+	Card             string   `json:"card"`                       // Card number of the payer
+	CardCVV          string   `json:"card_cvv"`                   // CVV/CVV2
+	CardExpMonth     string   `json:"card_exp_month"`             // Expiry month of the payer's card. For example: 08
+	CardExpYear      string   `json:"card_exp_year"`              // Expiry year of the payer's card. For example: 19
+	Currency         Currency `json:"currency"`                   // Payment currency. Possible values: USD, EUR, UAH
+	Description      string   `json:"description"`                // Payment description
+	IP               string   `json:"ip"`                         // Client IP
+	OrderID          string   `json:"order_id"`                   // Unique purchase ID in your shop. Maximum length is 255 symbols
+	Phone            string   `json:"phone,omitempty"`            // Payer's mobile phone. OTP password is sent to this phone number
+	Language         Language `json:"language,omitempty"`         // Customer's language uk, en
+	RecurringByToken string   `json:"recurringbytoken,omitempty"` // Generate payer card_token. Possible value: 1
+	ResultURL        string   `json:"result_url,omitempty"`       // URL of your shop where the buyer would be redirected after 3DS verification. Maximum length 510 symbols
+	ServerURL        string   `json:"server_url,omitempty"`       // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
+	DCC              string   `json:"dcc,omitempty"`              // Possible value Y. A foreign card payment stops in dcc_verify with an offer to pay in the card currency, see ConfirmDCC
+}
+
+type CardPaymentResponse struct {
+	AcqID              int        `json:"acq_id"`              // Acquirer ID
+	Action             Action     `json:"action"`              // Transaction type
+	AgentCommission    Amount     `json:"agent_commission"`    // Agent commission in payment currency
+	Amount             Amount     `json:"amount"`              // Payment amount
+	AmountBonus        Amount     `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
+	AmountCredit       Amount     `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
+	AmountDebit        Amount     `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
+	CardToken          string     `json:"card_token"`          // Sender's card token
+	CommissionCredit   Amount     `json:"commission_credit"`   // Commission from the receiver in currency_credit
+	CommissionDebit    Amount     `json:"commission_debit"`    // Commission from the sender in currency_debit
+	ConfirmPhone       string     `json:"confirm_phone"`       // Masked phone number the OTP password was sent to
+	CreateDate         Time       `json:"create_date"`         // Date of payment creation
+	Currency           Currency   `json:"currency"`            // Payment currency
+	CurrencyCredit     string     `json:"currency_credit"`     // Transaction currency of credit
+	CurrencyDebit      string     `json:"currency_debit"`      // Transaction currency of debit
+	DCCAmount          Amount     `json:"dcc_amount"`          // Payment amount converted to dcc_currency, offered in the dcc_verify status
+	DCCCurrency        Currency   `json:"dcc_currency"`        // Currency of the payer's card, offered in the dcc_verify status
+	DCCRate            Amount     `json:"dcc_rate"`            // Conversion rate: units of dcc_currency per unit of the payment currency
+	Description        string     `json:"description"`         // Payment description
+	EndDate            Time       `json:"end_date"`            // Date of payment edition/end
+	Is3DS              bool       `json:"is_3ds"`              // True if transaction passed with 3DS, false otherwise
+	LiqpayOrderID      string     `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	MPIECI             jsonString `json:"mpi_eci"`             // MPI ECI: 5 - transaction passed with 3DS, 6 - issuer of payer card doesn't support 3d Secure, 7 - operation passed without 3d Secure
+	OrderID            string     `json:"order_id"`            // Order_id payment
+	PaymentID          int        `json:"payment_id"`          // Payment id in LiqPay system
+	Paytype            string     `json:"paytype"`             // Method of payment
+	PublicKey          string     `json:"public_key"`          // Shop public key
+	ReceiverCommission Amount     `json:"receiver_commission"` // Receiver commission in payment currency
+	RedirectTo         string     `json:"redirect_to"`         // Link to redirect the client to, e.g. for 3DS verification
+	SenderBonus        Amount     `json:"sender_bonus"`        // Sender's bonus in the payment currency
+	SenderCardBank     string     `json:"sender_card_bank"`    // Sender's card bank
+	SenderCardCountry  int        `json:"sender_card_country"` // Sender's card country (digital ISO 3166-1 code)
+	SenderCardMask2    string     `json:"sender_card_mask2"`   // Sender's card mask2
+	SenderCardType     string     `json:"sender_card_type"`    // Sender's card type (MC/Visa)
+	SenderCommission   Amount     `json:"sender_commission"`   // Commission from the sender in the payment currency
+	Status             Status     `json:"status"`              // Payment status
+	Token              string     `json:"token"`               // Token to confirm the payment with OTP, CVV or the DCC choice
+	TransactionID      int64      `json:"transaction_id"`      // Id transactions in the LiqPay system
+	Version            int        `json:"version"`             // Version API
+}
+
+type WalletPaymentRequest struct {
+	Action           Action   `json:"action"`                     // Transaction type
+	Amount           Amount   `json:"amount"`                     // Payment amount. For example: 5, 7.34
+	Currency         Currency `json:"currency"`                   // Payment currency. Possible values: USD, EUR, UAH
+	Description      string   `json:"description"`                // Payment description
+	IP               string   `json:"ip"`                         // Client IP
+	OrderID          string   `json:"order_id"`                   // Unique purchase ID in your shop. Maximum length is 255 symbols
+	PayType          PayType  `json:"paytype"`                    // Wallet: apay, gpay. Set by the client
+	PaymentData      []byte   `json:"card"`                       // Wallet payment data: Apple Pay PKPaymentToken.paymentData or Google Pay tokenizationData.token. Sent base64 encoded
+	Phone            string   `json:"phone,omitempty"`            // Payer's mobile phone
+	Language         Language `json:"language,omitempty"`         // Customer's language uk, en
+	RecurringByToken string   `json:"recurringbytoken,omitempty"` // Generate payer card_token. Possible value: 1
+	ResultURL        string   `json:"result_url,omitempty"`       // URL of your shop where the buyer would be redirected after 3DS verification. Maximum length 510 symbols
+	ServerURL        string   `json:"server_url,omitempty"`       // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
+}
+
+type OTPConfirmRequest struct {
+	Action Action `json:"action"`       // Transaction type
+	Token  string `json:"token"`        // Token from the payment response
+	OTP    string `json:"confirm_code"` // OTP password sent to the payer's phone
+}
+
+type CVVConfirmRequest struct {
+	Action Action `json:"action"` // Transaction type
+	Token  string `json:"token"`  // Token from the payment response
+	CVV    string `json:"cvv"`    // CVV/CVV2 of the payer's card
+}
+
+type DCCConfirmRequest struct {
+	Action Action `json:"action"` // Transaction type
+	Token  string `json:"token"`  // Token from the payment response
+	DCC    string `json:"dcc"`    // Y to pay in the card currency at the offered rate, N to pay in the payment currency
+}
+
+type QRPaymentRequest struct {
+	Action      Action   `json:"action"`                // Transaction type
+	Amount      Amount   `json:"amount"`                // Payment amount. For example: 5, 7.34
+	Currency    Currency `json:"currency"`              // Payment currency. Possible values: USD, EUR, UAH
+	Description string   `json:"description"`           // Payment description
+	OrderID     string   `json:"order_id"`              // Unique purchase ID in your shop. Maximum length is 255 symbols
+	ExpiredDate Time     `json:"expired_date,omitzero"` // Date and time until which customer is able to scan the QR code and pay
+	Language    Language `json:"language,omitempty"`    // Customer's language uk, en
+	ServerURL   string   `json:"server_url,omitempty"`  // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
+}
+
+type QRPaymentResponse struct {
+	Action        Action   `json:"action"`          // Transaction type
+	Amount        Amount   `json:"amount"`          // Payment amount
+	Currency      Currency `json:"currency"`        // Payment currency
+	Description   string   `json:"description"`     // Payment description
+	LiqpayOrderID string   `json:"liqpay_order_id"` // Payment order_id in LiqPay system
+	OrderID       string   `json:"order_id"`        // Order_id payment
+	PaymentID     int64    `json:"payment_id"`      // Payment id in LiqPay system
+	QRCode        string   `json:"qr_code"`         // QR code payload to display to the customer
+	Status        Status   `json:"status"`          // Payment status, wait_qr until the customer scans the QR code
+}
+
+type CashPaymentRequest struct {
+	Action      Action   `json:"action"`                // Transaction type
+	Amount      Amount   `json:"amount"`                // Payment amount. For example: 5, 7.34
+	Currency    Currency `json:"currency"`              // Payment currency. Possible values: UAH
+	Description string   `json:"description"`           // Payment description
+	OrderID     string   `json:"order_id"`              // Unique purchase ID in your shop. Maximum length is 255 symbols
+	Phone       string   `json:"phone,omitempty"`       // Payer's mobile phone. The payment code is sent to this phone number
+	ExpiredDate Time     `json:"expired_date,omitzero"` // Date and time until which customer is able to pay at a terminal
+	Language    Language `json:"language,omitempty"`    // Customer's language uk, en
+	ServerURL   string   `json:"server_url,omitempty"`  // URL API in your store for notifications of payment status change (server -> server). Maximum length is 510 symbols
+}
+
+type CashPaymentResponse struct {
+	Action        Action   `json:"action"`          // Transaction type
+	Amount        Amount   `json:"amount"`          // Payment amount
+	Currency      Currency `json:"currency"`        // Payment currency
+	Description   string   `json:"description"`     // Payment description
+	ExpiredDate   Time     `json:"expired_date"`    // Date and time until which the payment code is valid
+	LiqpayOrderID string   `json:"liqpay_order_id"` // Payment order_id in LiqPay system
+	OrderID       string   `json:"order_id"`        // Order_id payment
+	PaymentCode   string   `json:"payment_code"`    // Code the customer enters at a self-service terminal to pay
+	PaymentID     int64    `json:"payment_id"`      // Payment id in LiqPay system
+	Status        Status   `json:"status"`          // Payment status, cash_wait until the customer pays
 }
 
 type SubscribePeriod string
@@ -158,7 +414,7 @@ const (
 
 type SubscriptionRequest struct {
 	Action             Action          `json:"action"`                          // Action to perform, e.g., "subscribe"
-	Amount             float64         `json:"amount"`                          // Payment amount. For example: 5, 7.34
+	Amount             Amount          `json:"amount"`                          // Payment amount. For example: 5, 7.34
 	Card               string          `json:"card"`                            // Card number of the payer
 	CardCVV            string          `json:"card_cvv"`                        // CVV/CVV2
 	CardExpMonth       string          `json:"card_exp_month"`                  // Expiry month of the payer's card. For example: 08
@@ -174,7 +430,7 @@ type SubscriptionRequest struct {
 	Recurring          bool            `json:"recurring,omitempty"`             // Token recurring payment flag
 	ServerURL          string          `json:"server_url,omitempty"`            // URL API in your store for notifications of payment status change
 	Subscribe          string          `json:"subscribe,omitempty"`             // Regular payment
-	SubscribeDateStart string          `json:"subscribe_date_start,omitempty"`  // Date of the first payment
+	SubscribeDateStart Time            `json:"subscribe_date_start,omitzero"`   // Date of the first payment
 	SubscribePeriod    SubscribePeriod `json:"subscribe_periodicity,omitempty"` // Period of payments
 	SenderAddress      string          `json:"sender_address,omitempty"`        // Sender's address
 	SenderCity         string          `json:"sender_city,omitempty"`           // Sender's city
@@ -192,48 +448,48 @@ type SubscriptionRequest struct {
 }
 
 type SubscriptionResponse struct {
-	AcqID              int64     `json:"acq_id"`              // Acquirer ID
-	Action             Action    `json:"action"`              // Transaction type
-	AgentCommission    float64   `json:"agent_commission"`    // Agent commission in payment currency
-	Amount             float64   `json:"amount"`              // Payment amount
-	AmountBonus        float64   `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
-	AmountCredit       float64   `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
-	AmountDebit        float64   `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
-	CardToken          string    `json:"card_token"`          // Sender's card token
-	CommissionCredit   float64   `json:"commission_credit"`   // Commission from the receiver in currency_credit
-	CommissionDebit    float64   `json:"commission_debit"`    // Commission from the sender in currency_debit
-	CreateDate         time.Time `json:"create_date"`         // Date of payment creation
-	Currency           Currency  `json:"currency"`            // Payment currency
-	CurrencyCredit     string    `json:"currency_credit"`     // Transaction currency of credit
-	CurrencyDebit      string    `json:"currency_debit"`      // Transaction currency of debit
-	Description        string    `json:"description"`         // Payment description
-	EndDate            string    `json:"end_date"`            // Date of payment edition/end
-	Is3DS              bool      `json:"is_3ds"`              // Whether the transaction passed with 3DS
-	LiqpayOrderID      string    `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
-	MPIECI             int64     `json:"mpi_eci"`             // MPI ECI value
-	OrderID            string    `json:"order_id"`            // Order_id payment
-	PaymentID          int64     `json:"payment_id"`          // Payment id in LiqPay system
-	PayType            string    `json:"paytype"`             // Methods of payment
-	PublicKey          string    `json:"public_key"`          // Shop public key
-	ReceiverCommission float64   `json:"receiver_commission"` // Receiver commission in payment currency
-	SenderBonus        float64   `json:"sender_bonus"`        // Sender's bonus in the payment currency
-	SenderCardBank     string    `json:"sender_card_bank"`    // Sender's card bank
-	SenderCardCountry  string    `json:"sender_card_country"` // Sender's card country
-	SenderCardMask2    string    `json:"sender_card_mask2"`   // Sender's card
-	SenderCardType     string    `json:"sender_card_type"`    // Sender's card type MC/Visa
-	SenderCommission   float64   `json:"sender_commission"`   // Commission from the sender in the payment currency
-	SenderPhone        string    `json:"sender_phone"`        // Sender's phone number
-	Status             Status    `json:"status"`              // Payment status
-	TransactionID      int64     `json:"transaction_id"`      // Id transactions in the LiqPay system
-	Version            int       `json:"version"`             // Version API
+	AcqID              int64      `json:"acq_id"`              // Acquirer ID
+	Action             Action     `json:"action"`              // Transaction type
+	AgentCommission    Amount     `json:"agent_commission"`    // Agent commission in payment currency
+	Amount             Amount     `json:"amount"`              // Payment amount
+	AmountBonus        Amount     `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
+	AmountCredit       Amount     `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
+	AmountDebit        Amount     `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
+	CardToken          string     `json:"card_token"`          // Sender's card token
+	CommissionCredit   Amount     `json:"commission_credit"`   // Commission from the receiver in currency_credit
+	CommissionDebit    Amount     `json:"commission_debit"`    // Commission from the sender in currency_debit
+	CreateDate         Time       `json:"create_date"`         // Date of payment creation
+	Currency           Currency   `json:"currency"`            // Payment currency
+	CurrencyCredit     string     `json:"currency_credit"`     // Transaction currency of credit
+	CurrencyDebit      string     `json:"currency_debit"`      // Transaction currency of debit
+	Description        string     `json:"description"`         // Payment description
+	EndDate            Time       `json:"end_date"`            // Date of payment edition/end
+	Is3DS              bool       `json:"is_3ds"`              // Whether the transaction passed with 3DS
+	LiqpayOrderID      string     `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	MPIECI             int64      `json:"mpi_eci"`             // MPI ECI value
+	OrderID            string     `json:"order_id"`            // Order_id payment
+	PaymentID          int64      `json:"payment_id"`          // Payment id in LiqPay system
+	PayType            string     `json:"paytype"`             // Methods of payment
+	PublicKey          string     `json:"public_key"`          // Shop public key
+	ReceiverCommission Amount     `json:"receiver_commission"` // Receiver commission in payment currency
+	SenderBonus        Amount     `json:"sender_bonus"`        // Sender's bonus in the payment currency
+	SenderCardBank     string     `json:"sender_card_bank"`    // Sender's card bank
+	SenderCardCountry  jsonString `json:"sender_card_country"` // Sender's card country
+	SenderCardMask2    string     `json:"sender_card_mask2"`   // Sender's card
+	SenderCardType     string     `json:"sender_card_type"`    // Sender's card type MC/Visa
+	SenderCommission   Amount     `json:"sender_commission"`   // Commission from the sender in the payment currency
+	SenderPhone        string     `json:"sender_phone"`        // Sender's phone number
+	Status             Status     `json:"status"`              // Payment status
+	TransactionID      int64      `json:"transaction_id"`      // Id transactions in the LiqPay system
+	Version            int        `json:"version"`             // Version API
 }
 
 type EditSubscriptionRequest struct {
-	Action      Action  `json:"action"`      // Action to be performed, in this case, 'subscribe_update'
-	Amount      float64 `json:"amount"`      // Payment amount. For example: 5, 7.34
-	Currency    string  `json:"currency"`    // Payment currency. Possible values: USD, EUR, UAH
-	Description string  `json:"description"` // Payment description
-	OrderID     string  `json:"order_id"`    // Unique purchase ID in your system
+	Action      Action `json:"action"`      // Action to be performed, in this case, 'subscribe_update'
+	Amount      Amount `json:"amount"`      // Payment amount. For example: 5, 7.34
+	Currency    string `json:"currency"`    // Payment currency. Possible values: USD, EUR, UAH
+	Description string `json:"description"` // Payment description
+	OrderID     string `json:"order_id"`    // Unique purchase ID in your system
 }
 
 type UnsubscribeRequest struct {
@@ -242,22 +498,22 @@ type UnsubscribeRequest struct {
 }
 
 type InvoiceItem struct {
-	Amount float64 `json:"amount"` // Price per unit
-	Count  int     `json:"count"`  // Number of units
-	Unit   string  `json:"unit"`   // Units of measurement
-	Name   string  `json:"name"`   // Name of the product or service
+	Amount Amount `json:"amount"` // Price per unit
+	Count  int    `json:"count"`  // Number of units
+	Unit   string `json:"unit"`   // Units of measurement
+	Name   string `json:"name"`   // Name of the product or service
 }
 
 type InvoiceRequest struct {
 	Action        Action        `json:"action"`                   // Action type, e.g., "invoice_send"
-	Amount        float64       `json:"amount"`                   // Payment amount. For example: 5, 7.34
+	Amount        Amount        `json:"amount"`                   // Payment amount. For example: 5, 7.34
 	Currency      Currency      `json:"currency"`                 // Payment currency. Possible values: USD, EUR, UAH
 	Description   string        `json:"description"`              // Payment description
 	Email         string        `json:"email"`                    // Customer's e-mail to send invoice (phone or email required parameters for transmission)
 	OrderID       string        `json:"order_id"`                 // Unique purchase ID in your shop. Maximum length is 255 symbols
 	Phone         string        `json:"phone"`                    // The phone number to which the invoice will be sent as a push notification to the Privat24 mobile application (phone or email required parameters for transmission)
 	ActionPayment string        `json:"action_payment,omitempty"` // Transaction type. Possible values: pay, hold, subscribe, paydonate
-	ExpiredDate   string        `json:"expired_date,omitempty"`   // Date and time until which customer is able to pay invoice by UTC. Should be sent in the following format 2016-04-24 00:00:00
+	ExpiredDate   Time          `json:"expired_date,omitzero"`    // Date and time until which customer is able to pay invoice
 	Goods         []InvoiceItem `json:"goods,omitempty"`          // Optional list of goods
 	Language      Language      `json:"language,omitempty"`       // Customer's language uk, en
 	ResultURL     string        `json:"result_url,omitempty"`     // URL of your shop where the buyer would be redirected after completion of the purchase. Maximum length 510 symbols
@@ -266,7 +522,7 @@ type InvoiceRequest struct {
 
 type InvoiceResponse struct {
 	Action        Action   `json:"action"`          // Transaction type. Possible values: pay, hold, paysplit, subscribe, paydonate, auth, regular
-	Amount        float64  `json:"amount"`          // Payment amount
+	Amount        Amount   `json:"amount"`          // Payment amount
 	Currency      Currency `json:"currency"`        // Payment currency
 	Description   string   `json:"description"`     // Payment description
 	Href          string   `json:"href"`            // Link to invoice
@@ -274,7 +530,7 @@ type InvoiceResponse struct {
 	OrderID       string   `json:"order_id"`        // Order_id payment
 	ReceiverType  string   `json:"receiver_type"`   // Receive channel type
 	ReceiverValue string   `json:"receiver_value"`  // The value obtained in the parameter receiver_type
-	Status        string   `json:"status"`          // Payment status. Possible values: error, failure, success, invoice_wait, token
+	Status        Status   `json:"status"`          // Payment status. Possible values: error, failure, success, invoice_wait, token
 	Token         string   `json:"token,omitempty"` // Payment token
 }
 
@@ -292,65 +548,151 @@ const (
 
 type CancelInvoiceResponse struct {
 	InvoiceID int64               `json:"invoice_id"` // Unique identifier of the invoice
-	Result    CancelInvoiceResult `json:"order_id"`   // The result of a request ok or error
+	Result    CancelInvoiceResult `json:"result"`     // The result of a request ok or error
+}
+
+type ReportFormat string
+
+const (
+	ReportFormatJSON ReportFormat = "json" // Report rows in JSON
+	ReportFormatCSV  ReportFormat = "csv"  // Report rows in CSV
+)
+
+type ReportsRequest struct {
+	Action     Action       `json:"action"`                // Transaction type
+	DateFrom   int64        `json:"date_from"`             // Start of the period, UTC timestamp in milliseconds
+	DateTo     int64        `json:"date_to"`               // End of the period, UTC timestamp in milliseconds
+	RespFormat ReportFormat `json:"resp_format,omitempty"` // Response format: json, csv
+}
+
+type ReportsResponse struct {
+	Result string          `json:"result"` // The result of a request
+	Data   []ReportPayment `json:"data"`   // Payments created within the period
+}
+
+type ReportPayment struct {
+	AcqID              int        `json:"acq_id"`              // Acquirer ID
+	Action             Action     `json:"action"`              // Transaction type
+	AgentCommission    Amount     `json:"agent_commission"`    // Agent commission in payment currency
+	Amount             Amount     `json:"amount"`              // Payment amount
+	AmountBonus        Amount     `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
+	AmountCredit       Amount     `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
+	AmountDebit        Amount     `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
+	CommissionCredit   Amount     `json:"commission_credit"`   // Commission from the receiver in currency_credit
+	CommissionDebit    Amount     `json:"commission_debit"`    // Commission from the sender in currency_debit
+	CreateDate         Time       `json:"create_date"`         // Date of payment creation
+	Currency           Currency   `json:"currency"`            // Payment currency
+	CurrencyCredit     string     `json:"currency_credit"`     // Transaction currency of credit
+	CurrencyDebit      string     `json:"currency_debit"`      // Transaction currency of debit
+	Description        string     `json:"description"`         // Payment description
+	EndDate            Time       `json:"end_date"`            // Date of payment edition/end
+	ErrCode            jsonString `json:"err_code"`            // Error code of a failed payment
+	ErrDescription     string     `json:"err_description"`     // Error description of a failed payment
+	Info               string     `json:"info"`                // Additional payment information
+	IP                 string     `json:"ip"`                  // Sender's IP address
+	Is3DS              bool       `json:"is_3ds"`              // True if transaction passed with 3DS, false otherwise
+	LiqpayOrderID      string     `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	MPIECI             jsonString `json:"mpi_eci"`             // MPI ECI: 5 - transaction passed with 3DS, 6 - issuer of payer card doesn't support 3d Secure, 7 - operation passed without 3d Secure
+	OrderID            string     `json:"order_id"`            // Order_id payment
+	PaymentID          int64      `json:"payment_id"`          // Payment id in LiqPay system
+	Paytype            string     `json:"paytype"`             // Method of payment: card, privat24, moment_part, cash, invoice, qr
+	ReceiverCommission Amount     `json:"receiver_commission"` // Receiver commission in payment currency
+	SenderBonus        Amount     `json:"sender_bonus"`        // Sender's bonus in the payment currency
+	SenderCardBank     string     `json:"sender_card_bank"`    // Sender's card bank
+	SenderCardCountry  int        `json:"sender_card_country"` // Sender's card country (digital ISO 3166-1 code)
+	SenderCardMask2    string     `json:"sender_card_mask2"`   // Sender's card mask2
+	SenderCardType     string     `json:"sender_card_type"`    // Sender's card type (MC/Visa)
+	SenderCommission   Amount     `json:"sender_commission"`   // Commission from the sender in the payment currency
+	SenderPhone        string     `json:"sender_phone"`        // Sender's phone number
+	Status             Status     `json:"status"`              // Payment status
+	TransactionID      int64      `json:"transaction_id"`      // Transaction id in LiqPay system
+}
+
+type CompensationReportRequest struct {
+	Action     Action       `json:"action"`                // Transaction type
+	Date       string       `json:"date"`                  // Date of the compensation in the format 2006-01-02
+	RespFormat ReportFormat `json:"resp_format,omitempty"` // Response format: json, csv
+}
+
+type CompensationReportResponse struct {
+	Result string               `json:"result"` // The result of a request
+	Data   []CompensationRecord `json:"data"`   // Payments compensated on the date
+}
+
+type CompensationRecord struct {
+	Action             Action   `json:"action"`              // Transaction type
+	Amount             Amount   `json:"amount"`              // Payment amount
+	AmountCredit       Amount   `json:"amount_credit"`       // Amount transferred to the receiver in currency of currency_credit
+	CompensationDate   Time     `json:"compensation_date"`   // Date of the compensation
+	CompensationID     int64    `json:"compensation_id"`     // Compensation id in LiqPay system
+	CreateDate         Time     `json:"create_date"`         // Date of payment creation
+	Currency           Currency `json:"currency"`            // Payment currency
+	CurrencyCredit     string   `json:"currency_credit"`     // Transaction currency of credit
+	Description        string   `json:"description"`         // Payment description
+	EndDate            Time     `json:"end_date"`            // Date of payment edition/end
+	LiqpayOrderID      string   `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	OrderID            string   `json:"order_id"`            // Order_id payment
+	PaymentID          int64    `json:"payment_id"`          // Payment id in LiqPay system
+	ReceiverCommission Amount   `json:"receiver_commission"` // Receiver commission in payment currency
+	Status             Status   `json:"status"`              // Payment status
 }
 
 type Callback struct {
-	AcqID              int     `json:"acq_id"`              // ID of the acquirer
-	Action             Action  `json:"action"`              // Type of operation: pay, hold, paysplit, subscribe, auth, regular
-	AgentCommission    float64 `json:"agent_commission"`    // Agent commission in payment currency
-	Amount             float64 `json:"amount"`              // Payment amount
-	AmountBonus        float64 `json:"amount_bonus"`        // Sender's bonus in payment currency (debit)
-	AmountCredit       float64 `json:"amount_credit"`       // Amount of credit transaction in currency_credit
-	AmountDebit        float64 `json:"amount_debit"`        // Amount of debit transaction in currency_debit
-	AuthcodeCredit     string  `json:"authcode_credit"`     // Authorization code for credit transaction
-	AuthcodeDebit      string  `json:"authcode_debit"`      // Authorization code for debit transaction
-	CardToken          string  `json:"card_token"`          // Sender's card token
-	CommissionCredit   float64 `json:"commission_credit"`   // Receiver's commission in currency_credit
-	CommissionDebit    float64 `json:"commission_debit"`    // Sender's commission in currency_debit
-	CompletionDate     string  `json:"completion_date"`     // Date of funds debit
-	CreateDate         string  `json:"create_date"`         // Payment creation date
-	Currency           string  `json:"currency"`            // Payment currency
-	CurrencyCredit     string  `json:"currency_credit"`     // Currency of credit transaction
-	CurrencyDebit      string  `json:"currency_debit"`      // Currency of debit transaction
-	Customer           string  `json:"customer"`            // Unique identifier of the customer on merchant's site
-	Description        string  `json:"description"`         // Payment comment
-	EndDate            string  `json:"end_date"`            // End/change date of payment
-	ErrCode            string  `json:"err_code"`            // Error code
-	ErrDescription     string  `json:"err_description"`     // Error description
-	Info               string  `json:"info"`                // Additional information about the payment
-	IP                 string  `json:"ip"`                  // Sender's IP address
-	Is3DS              bool    `json:"is_3ds"`              // Indicates if the transaction passed 3DS verification
-	LiqpayOrderID      string  `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
-	MpiEci             int     `json:"mpi_eci"`             // MPI ECI value
-	OrderID            string  `json:"order_id"`            // Payment order_id
-	PaymentID          int     `json:"payment_id"`          // Payment ID in LiqPay system
-	Paytype            string  `json:"paytype"`             // Payment method: card, privat24, masterpass, moment_part, cash, invoice, qr
-	PublicKey          string  `json:"public_key"`          // Merchant's public key
-	ReceiverCommission float64 `json:"receiver_commission"` // Receiver's commission in payment currency
-	RedirectTo         string  `json:"redirect_to"`         // Link to redirect the client for 3DS verification
-	RefundDateLast     string  `json:"refund_date_last"`    // Last refund date for the payment
-	RRNCredit          string  `json:"rrn_credit"`          // Unique transaction number in issuer and acquiring bank's system (credit)
-	RRNDebit           string  `json:"rrn_debit"`           // Unique transaction number in issuer and acquiring bank's system (debit)
-	SenderBonus        float64 `json:"sender_bonus"`        // Sender's bonus in payment currency
-	SenderCardBank     string  `json:"sender_card_bank"`    // Sender's card bank
-	SenderCardCountry  string  `json:"sender_card_country"` // Sender's card country ISO 3166-1 code
-	SenderCardMask2    string  `json:"sender_card_mask2"`   // Sender's card mask
-	SenderCardType     string  `json:"sender_card_type"`    // Sender's card type (MC/Visa)
-	SenderCommission   float64 `json:"sender_commission"`   // Sender's commission in payment currency
-	SenderFirstName    string  `json:"sender_first_name"`   // Sender's first name
-	SenderLastName     string  `json:"sender_last_name"`    // Sender's last name
-	SenderPhone        string  `json:"sender_phone"`        // Sender's phone number
-	Status             string  `json:"status"`              // Payment status
-	WaitReserveStatus  string  `json:"wait_reserve_status"` // Additional payment status indicating that the current payment is reserved for refund
-	Token              string  `json:"token"`               // Payment token
-	Type               string  `json:"type"`                // Payment type
-	Version            int     `json:"version"`             // API version
-	ErrErc             string  `json:"err_erc"`             // Error code
-	ProductCategory    string  `json:"product_category"`    // Product category
-	ProductDescription string  `json:"product_description"` // Product description
-	ProductName        string  `json:"product_name"`        // Product name
-	ProductURL         string  `json:"product_url"`         // Product page URL
-	RefundAmount       float64 `json:"refund_amount"`       // Refund amount
-	Verifycode         string  `json:"verifycode"`          // Verification code
+	AcqID              int        `json:"acq_id"`              // ID of the acquirer
+	Action             Action     `json:"action"`              // Type of operation: pay, hold, paysplit, subscribe, auth, regular
+	AgentCommission    Amount     `json:"agent_commission"`    // Agent commission in payment currency
+	Amount             Amount     `json:"amount"`              // Payment amount
+	AmountBonus        Amount     `json:"amount_bonus"`        // Sender's bonus in payment currency (debit)
+	AmountCredit       Amount     `json:"amount_credit"`       // Amount of credit transaction in currency_credit
+	AmountDebit        Amount     `json:"amount_debit"`        // Amount of debit transaction in currency_debit
+	AuthcodeCredit     jsonString `json:"authcode_credit"`     // Authorization code for credit transaction
+	AuthcodeDebit      jsonString `json:"authcode_debit"`      // Authorization code for debit transaction
+	CardToken          string     `json:"card_token"`          // Sender's card token
+	CommissionCredit   Amount     `json:"commission_credit"`   // Receiver's commission in currency_credit
+	CommissionDebit    Amount     `json:"commission_debit"`    // Sender's commission in currency_debit
+	CompletionDate     Time       `json:"completion_date"`     // Date of funds debit
+	CreateDate         Time       `json:"create_date"`         // Payment creation date
+	Currency           string     `json:"currency"`            // Payment currency
+	CurrencyCredit     string     `json:"currency_credit"`     // Currency of credit transaction
+	CurrencyDebit      string     `json:"currency_debit"`      // Currency of debit transaction
+	Customer           string     `json:"customer"`            // Unique identifier of the customer on merchant's site
+	Description        string     `json:"description"`         // Payment comment
+	EndDate            Time       `json:"end_date"`            // End/change date of payment
+	ErrCode            jsonString `json:"err_code"`            // Error code
+	ErrDescription     string     `json:"err_description"`     // Error description
+	Info               string     `json:"info"`                // Additional information about the payment
+	IP                 string     `json:"ip"`                  // Sender's IP address
+	Is3DS              bool       `json:"is_3ds"`              // Indicates if the transaction passed 3DS verification
+	LiqpayOrderID      string     `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	MpiEci             int        `json:"mpi_eci"`             // MPI ECI value
+	OrderID            string     `json:"order_id"`            // Payment order_id
+	PaymentID          int        `json:"payment_id"`          // Payment ID in LiqPay system
+	Paytype            string     `json:"paytype"`             // Payment method: card, privat24, masterpass, moment_part, cash, invoice, qr
+	PublicKey          string     `json:"public_key"`          // Merchant's public key
+	ReceiverCommission Amount     `json:"receiver_commission"` // Receiver's commission in payment currency
+	RedirectTo         string     `json:"redirect_to"`         // Link to redirect the client for 3DS verification
+	RefundDateLast     Time       `json:"refund_date_last"`    // Last refund date for the payment
+	RRNCredit          jsonString `json:"rrn_credit"`          // Unique transaction number in issuer and acquiring bank's system (credit)
+	RRNDebit           jsonString `json:"rrn_debit"`           // Unique transaction number in issuer and acquiring bank's system (debit)
+	SenderBonus        Amount     `json:"sender_bonus"`        // Sender's bonus in payment currency
+	SenderCardBank     string     `json:"sender_card_bank"`    // Sender's card bank
+	SenderCardCountry  jsonString `json:"sender_card_country"` // Sender's card country ISO 3166-1 code
+	SenderCardMask2    string     `json:"sender_card_mask2"`   // Sender's card mask
+	SenderCardType     string     `json:"sender_card_type"`    // Sender's card type (MC/Visa)
+	SenderCommission   Amount     `json:"sender_commission"`   // Sender's commission in payment currency
+	SenderFirstName    string     `json:"sender_first_name"`   // Sender's first name
+	SenderLastName     string     `json:"sender_last_name"`    // Sender's last name
+	SenderPhone        string     `json:"sender_phone"`        // Sender's phone number
+	Status             Status     `json:"status"`              // Payment status
+	WaitReserveStatus  Status     `json:"wait_reserve_status"` // Additional payment status indicating that the current payment is reserved for refund
+	Token              string     `json:"token"`               // Payment token
+	Type               string     `json:"type"`                // Payment type
+	Version            int        `json:"version"`             // API version
+	ErrErc             jsonString `json:"err_erc"`             // Error code
+	ProductCategory    string     `json:"product_category"`    // Product category
+	ProductDescription string     `json:"product_description"` // Product description
+	ProductName        string     `json:"product_name"`        // Product name
+	ProductURL         string     `json:"product_url"`         // Product page URL
+	RefundAmount       Amount     `json:"refund_amount"`       // Refund amount
+	Verifycode         string     `json:"verifycode"`          // Verification code
 }
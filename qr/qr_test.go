@@ -0,0 +1,288 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// TestEncode compares codes with reference bitmaps produced by an independent encoder
+// (rsc.io/qr/coding) for the same version, level and mask. '#' is a dark module.
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		level   Level
+		version int
+		want    []string
+	}{
+		{
+			name:    "version 1",
+			text:    "HELLO WORLD",
+			level:   LevelM,
+			version: 1,
+			// Reference encoded with mask 4.
+			want: []string{
+				"#######.##..#.#######",
+				"#.....#....#..#.....#",
+				"#.###.#..#.#..#.###.#",
+				"#.###.#.#..#..#.###.#",
+				"#.###.#.###.#.#.###.#",
+				"#.....#.#..#..#.....#",
+				"#######.#.#.#.#######",
+				"........#..##........",
+				"#...#.######.#####..#",
+				"...#....#.###....####",
+				"..######..##.##.#..#.",
+				"#####...##...#.......",
+				"#####.#.#.#.#.##..##.",
+				"........#.#.####.#.##",
+				"#######.###.#.#.##.#.",
+				"#.....#..#.###.##..##",
+				"#.###.#.##.#.##...##.",
+				"#.###.#..#..#...##.##",
+				"#.###.#..###...###...",
+				"#.....#....#.#.......",
+				"#######.#########.#.#",
+			},
+		},
+		{
+			name:    "version 2",
+			text:    "01234567",
+			level:   LevelH,
+			version: 2,
+			// Reference encoded with mask 7.
+			want: []string{
+				"#######.#.##.##...#######",
+				"#.....#.#.##..#...#.....#",
+				"#.###.#..#....#...#.###.#",
+				"#.###.#.#..#......#.###.#",
+				"#.###.#.###.#.##..#.###.#",
+				"#.....#.#..##.#.#.#.....#",
+				"#######.#.#.#.#.#.#######",
+				".........#...#.##........",
+				"...#..#..#....#.#..###.##",
+				".##.#...#..####.##..##..#",
+				"#....##.#.#...#.#.##..#.#",
+				"#.#.##.#.###..##.#...#.#.",
+				"####..#..###...#..#.##..#",
+				"...##....#####.##....##.#",
+				"#..#.##..#....#....###.##",
+				".#.###.....#...#.##..#.#.",
+				"####..###...#...#######.#",
+				"........#.#.#.###...#..##",
+				"#######...##..#.#.#.#.#.#",
+				"#.....#...##....#...####.",
+				"#.###.#...###.########.#.",
+				"#.###.#.#.#.#####.#.#..#.",
+				"#.###.#....##..##.####..#",
+				"#.....#...#.#.#....#.#...",
+				"#######....#....######.##",
+			},
+		},
+		{
+			name:    "version 8 with version information",
+			text:    "https://www.liqpay.ua/api/3/checkout?data=" + strings.Repeat("eyJ2ZXJzaW9uIjozfQ", 4) + "&signature=QvJD5u9Fg55PCx",
+			level:   LevelM,
+			version: 8,
+			// Reference encoded with mask 6.
+			want: []string{
+				"#######.#..###.#....#.#....#..#.#.#.#...#.#######",
+				"#.....#.#..#.#...##.#.#.#######.#####.###.#.....#",
+				"#.###.#.##.######.#.#.#.#.#..#..###..#.##.#.###.#",
+				"#.###.#..##....#.#..##.#.#.###.##.####.#..#.###.#",
+				"#.###.#.##..#....###.########.###...##....#.###.#",
+				"#.....#..#.##....#..#.#...#..#.#...#.##...#.....#",
+				"#######.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#######",
+				".........##..###..#..##...##..#.##.#.#.#.........",
+				"#..######.#.#...#.#...#####.###...####...#..#.###",
+				".###....#.......##.####..##..##..######.########.",
+				"#..#####...##.###..........###.###.#..#.....###.#",
+				"#..##........####..#.##...#..#..#.....####.####.#",
+				"..##.#####..#...##..###.#####.####..##...#.##.###",
+				".....#...#.........##..##.#####.##.##.##..##...#.",
+				"#.#.###......#.#.##...#..#.##.##.#..####...##..##",
+				"..##.#..####.#####..#.###..##.##.###.#.....#.##.#",
+				"...#..##.###....##......#..#.##.#..####...######.",
+				"#...#....##.#.##..##....##.##.##..####.##.##.##..",
+				"..#..##..#.#...#.###...###.####.#...#.##.##.###.#",
+				".#..##..#.###.....##.#...##...##....#.#.#.#..#...",
+				"##.#.###...##.###..###.##...###...###.#....#...##",
+				"...#.#..##....##.#.##..##.#.#########.######.##..",
+				"....#######.#..###...########...#.#.#...######..#",
+				"#.#.#...###...##....###...###.#.#.....###...####.",
+				"###.#.#.#..#.##..##..##.#.#.#.#.#.####..#.#.##.#.",
+				"...##...#.##...#..#####...#.####.#.#..#.#...#....",
+				"..#########...##.#.#..########.###.##.#######.###",
+				"#...#..#...###...#####.#####.....#.#..##.##.###.#",
+				"#.#.#.##.#.##...#.#.##..#.##.#.##.#.####..#..##.#",
+				"##......##.#...#.####..#####.###.##.##.#####...#.",
+				"##....##.##.##.#..##....#.#.....#..#..###.##..#.#",
+				".#####...#.#######...#..##....#...#.###.##...#.#.",
+				"##.##.####.#...###..#..#.##..#.#..###..###.#.#...",
+				"#.####..#........#.####.#....###.##..##.##.####..",
+				"##...###..#.#.#.####.##..##.#..###.#.#..######.##",
+				".#####.#####.##..#.####.########.....##..#..###.#",
+				"##....####.##..#####...#.##.###.#.#.##.###.#...##",
+				"######.........##.#.#.###.#.####...##.##....#..#.",
+				".#...##..##...#####........#.....#...###..#...###",
+				".###...####.#..##.#......###...#...##..#.#.#..##.",
+				"###...##....##.##.##..######..#####.#..######.#..",
+				"........###.#.#.#.##..#...#...#####.##.##...####.",
+				"#######.###..######.#.#.#.##.##..#.###..#.#.#.#.#",
+				"#.....#.#..##....#..#.#...#.#.#...#...###...##.#.",
+				"#.###.#.##...#..###...#######....#.##.#.#####..#.",
+				"#.###.#.#..##...##..#.#..#.####..##.#.##...#.##.#",
+				"#.###.#...#.#.##..#####.#..##.##.##..#...#.##.##.",
+				"#.....#..###...#.#.....####.##..######.##.#..####",
+				"#######.##..#.#.####..##.######.##..##..#.####..#",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode(tt.text, tt.level)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if c.Version != tt.version || c.Size != len(tt.want) {
+				t.Fatalf("Encode() version = %d, size = %d, want %d, %d", c.Version, c.Size, tt.version, len(tt.want))
+			}
+
+			for y, row := range tt.want {
+				var got strings.Builder
+				for x := range c.Size {
+					if c.At(x, y) {
+						got.WriteByte('#')
+					} else {
+						got.WriteByte('.')
+					}
+				}
+				if got.String() != row {
+					t.Errorf("row %d = %s, want %s", y, got.String(), row)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		level   Level
+		wantErr error
+	}{
+		{name: "invalid level", text: "HELLO WORLD", level: LevelH + 1},
+		{name: "too long", text: strings.Repeat("a", 2954), level: LevelL, wantErr: ErrTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Encode(tt.text, tt.level)
+			if err == nil {
+				t.Fatal("Encode() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Encode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	c, err := Encode("HELLO WORLD", LevelM)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	img := c.Image(3, 4)
+	if size := (c.Size + 8) * 3; img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+		t.Fatalf("Image() bounds = %v, want %dx%d", img.Bounds(), size, size)
+	}
+
+	for _, p := range []struct{ x, y int }{{0, 0}, {11, 11}, {12, 12}, {14, 14}, {30, 12}} {
+		r, _, _, _ := img.At(p.x, p.y).RGBA()
+		if dark := c.At(p.x/3-4, p.y/3-4); (r == 0) != dark {
+			t.Errorf("Image() pixel %d,%d dark = %t, want %t", p.x, p.y, r == 0, dark)
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	c, err := Encode("https://www.liqpay.ua/api/3/checkout?data=eyJ2ZXJzaW9uIjozfQ&signature=abc", LevelM)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	data, err := c.PNG(2, 4)
+	if err != nil {
+		t.Fatalf("PNG() error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG() is not a png: %v", err)
+	}
+
+	if size := (c.Size + 8) * 2; img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+		t.Fatalf("PNG() bounds = %v, want %dx%d", img.Bounds(), size, size)
+	}
+	for y := range c.Size + 8 {
+		for x := range c.Size + 8 {
+			r, _, _, _ := img.At(x*2, y*2).RGBA()
+			if dark := c.At(x-4, y-4); (r == 0) != dark {
+				t.Fatalf("PNG() module %d,%d dark = %t, want %t", x-4, y-4, r == 0, dark)
+			}
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	c, err := Encode("HELLO WORLD", LevelQ)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	tests := []struct {
+		border      int
+		wantViewBox string
+		wantFirst   string // Top left module of the finder pattern
+	}{
+		{border: 4, wantViewBox: `viewBox="0 0 29 29"`, wantFirst: "M4,4h1v1h-1z"},
+		{border: 0, wantViewBox: `viewBox="0 0 21 21"`, wantFirst: "M0,0h1v1h-1z"},
+		{border: -1, wantViewBox: `viewBox="0 0 21 21"`, wantFirst: "M0,0h1v1h-1z"},
+	}
+
+	dark := 0
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.At(x, y) {
+				dark++
+			}
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.border), func(t *testing.T) {
+			svg := string(c.SVG(tt.border))
+
+			if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+				t.Fatalf("SVG() is not well-formed xml: %v", err)
+			}
+			if !strings.Contains(svg, tt.wantViewBox) {
+				t.Errorf("SVG() = %.120s..., want %s", svg, tt.wantViewBox)
+			}
+			if !strings.Contains(svg, `d="`+tt.wantFirst) {
+				t.Errorf("SVG() path does not start with %s", tt.wantFirst)
+			}
+			if got := strings.Count(svg, "h1v1h-1z"); got != dark {
+				t.Errorf("SVG() modules = %d, want %d", got, dark)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package liqpay
+
+import "context"
+
+// CreateQRPayment creates a payment to be paid by scanning a QR code. The QR code payload is returned in
+// QRPaymentResponse.QRCode and can be rendered with the qr package. The payment stays in the wait_qr status
+// until the customer pays. An expired QR code is reported with the expired_qr error, matched by ErrQRCodeExpired.
+func (c client) CreateQRPayment(data *QRPaymentRequest) (*QRPaymentResponse, error) {
+	return c.CreateQRPaymentContext(context.Background(), data)
+}
+
+// CreateQRPaymentContext creates a payment to be paid by scanning a QR code using the provided context.
+func (c client) CreateQRPaymentContext(ctx context.Context, data *QRPaymentRequest) (*QRPaymentResponse, error) {
+	data.Action = ActionPayQR
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &QRPaymentResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
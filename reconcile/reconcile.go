@@ -0,0 +1,254 @@
+// Package reconcile compares orders from a merchant ledger with LiqPay payment records,
+// e.g. rows of the payments archive, and reports the discrepancies between them.
+//
+// Orders and payments are matched by payment_id, then by liqpay_order_id, then by order_id,
+// using the first identifier that is set on both sides.
+package reconcile
+
+import (
+	"iter"
+
+	"github.com/kabachoksolutions/liqpay"
+)
+
+// Order is an order record from the merchant ledger.
+type Order struct {
+	OrderID       string          // Unique purchase ID in the shop
+	LiqpayOrderID string          // Payment order_id in LiqPay system, if known
+	PaymentID     int64           // Payment id in LiqPay system, if known
+	Amount        liqpay.Amount   // Order amount
+	Currency      liqpay.Currency // Order currency
+	Status        liqpay.Status   // Payment status as recorded in the ledger
+}
+
+// Payment is a payment record from LiqPay.
+type Payment struct {
+	OrderID            string          // Unique purchase ID in the shop
+	LiqpayOrderID      string          // Payment order_id in LiqPay system
+	PaymentID          int64           // Payment id in LiqPay system
+	Amount             liqpay.Amount   // Payment amount
+	Currency           liqpay.Currency // Payment currency
+	Status             liqpay.Status   // Payment status
+	SenderCommission   liqpay.Amount   // Commission from the sender in the payment currency
+	ReceiverCommission liqpay.Amount   // Receiver commission in the payment currency
+	AgentCommission    liqpay.Amount   // Agent commission in the payment currency
+}
+
+// PaymentFromReport converts a payments archive row to a Payment.
+func PaymentFromReport(p liqpay.ReportPayment) Payment {
+	return Payment{
+		OrderID:            p.OrderID,
+		LiqpayOrderID:      p.LiqpayOrderID,
+		PaymentID:          p.PaymentID,
+		Amount:             p.Amount,
+		Currency:           p.Currency,
+		Status:             p.Status,
+		SenderCommission:   p.SenderCommission,
+		ReceiverCommission: p.ReceiverCommission,
+		AgentCommission:    p.AgentCommission,
+	}
+}
+
+// PaymentFromStatus converts a payment status response to a Payment.
+func PaymentFromStatus(s *liqpay.StatusResponse) Payment {
+	return Payment{
+		OrderID:            s.OrderID,
+		LiqpayOrderID:      s.LiqpayOrderID,
+		PaymentID:          int64(s.PaymentID),
+		Amount:             s.Amount,
+		Currency:           s.Currency,
+		Status:             s.Status,
+		SenderCommission:   s.SenderCommission,
+		ReceiverCommission: s.ReceiverCommission,
+		AgentCommission:    s.AgentCommission,
+	}
+}
+
+// ReportPayments returns an iterator over the payments of a payments archive.
+func ReportPayments(report *liqpay.ReportsResponse) iter.Seq[Payment] {
+	return func(yield func(Payment) bool) {
+		for _, p := range report.Data {
+			if !yield(PaymentFromReport(p)) {
+				return
+			}
+		}
+	}
+}
+
+// Kind is a kind of discrepancy between the ledger and LiqPay.
+type Kind string
+
+const (
+	KindMissingInLiqPay  Kind = "missing_in_liqpay" // Order has no matching LiqPay payment
+	KindMissingInLedger  Kind = "missing_in_ledger" // LiqPay payment has no matching order
+	KindDuplicatePayment Kind = "duplicate_payment" // Order matches more than one LiqPay payment
+	KindDuplicateOrder   Kind = "duplicate_order"   // Order has the same order_id, liqpay_order_id or payment_id as an earlier order in the ledger
+	KindAmountMismatch   Kind = "amount_mismatch"   // Order and payment amounts differ
+	KindCurrencyMismatch Kind = "currency_mismatch" // Order and payment currencies differ
+	KindStatusMismatch   Kind = "status_mismatch"   // Order and payment statuses differ, e.g. success in the ledger and reversed in LiqPay
+)
+
+// Discrepancy is a difference between an order and a LiqPay payment.
+// Order is nil for KindMissingInLedger and Payment is nil for KindMissingInLiqPay and KindDuplicateOrder.
+type Discrepancy struct {
+	Kind    Kind
+	Order   *Order
+	Payment *Payment
+}
+
+// Commission is the total of LiqPay commissions in a currency.
+type Commission struct {
+	Sender   liqpay.Amount // Total commission from senders
+	Receiver liqpay.Amount // Total commission from the receiver
+	Agent    liqpay.Amount // Total agent commission
+}
+
+// Total returns the sum of the sender, receiver and agent commissions.
+func (c Commission) Total() liqpay.Amount {
+	return c.Sender.Add(c.Receiver).Add(c.Agent)
+}
+
+// Result is the outcome of a reconciliation.
+type Result struct {
+	Matched       int                            // Number of matched order and payment pairs, including the ones with discrepancies
+	Discrepancies []Discrepancy                  // Duplicate orders, followed by discrepancies in the order of the payments, followed by orders missing in LiqPay
+	Commissions   map[liqpay.Currency]Commission // Commission totals of all LiqPay payments by currency
+}
+
+// Options configures a reconciliation.
+type Options struct {
+	// StatusEqual reports whether the ledger status matches the LiqPay status.
+	// Defaults to DefaultStatusEqual.
+	StatusEqual func(ledger, lp liqpay.Status) bool
+}
+
+// DefaultStatusEqual reports whether the statuses are equal, or both successful, or both failed.
+func DefaultStatusEqual(ledger, lp liqpay.Status) bool {
+	switch {
+	case ledger == lp:
+		return true
+	case ledger.IsSuccessful() && lp.IsSuccessful():
+		return true
+	case ledger.IsFailed() && lp.IsFailed():
+		return true
+	}
+	return false
+}
+
+// Reconcile matches the orders with the LiqPay payments and reports the discrepancies between them.
+// The orders are read fully into memory before the payments are read. An order that shares an identifier
+// with an earlier order is reported as KindDuplicateOrder and is not matched with the payments.
+func Reconcile(orders iter.Seq[Order], payments iter.Seq[Payment], opts *Options) *Result {
+	statusEqual := DefaultStatusEqual
+	if opts != nil && opts.StatusEqual != nil {
+		statusEqual = opts.StatusEqual
+	}
+
+	result := &Result{Commissions: map[liqpay.Currency]Commission{}}
+
+	var (
+		ledger          []*Order
+		byPaymentID     = map[int64]*Order{}
+		byLiqpayOrderID = map[string]*Order{}
+		byOrderID       = map[string]*Order{}
+	)
+	for o := range orders {
+		order := &o
+		if isDuplicate(order, byPaymentID, byLiqpayOrderID, byOrderID) {
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindDuplicateOrder, Order: order})
+			continue
+		}
+
+		ledger = append(ledger, order)
+		if order.PaymentID != 0 {
+			byPaymentID[order.PaymentID] = order
+		}
+		if order.LiqpayOrderID != "" {
+			byLiqpayOrderID[order.LiqpayOrderID] = order
+		}
+		if order.OrderID != "" {
+			byOrderID[order.OrderID] = order
+		}
+	}
+
+	matched := map[*Order]bool{}
+
+	for p := range payments {
+		payment := &p
+
+		commission := result.Commissions[payment.Currency]
+		commission.Sender = commission.Sender.Add(payment.SenderCommission)
+		commission.Receiver = commission.Receiver.Add(payment.ReceiverCommission)
+		commission.Agent = commission.Agent.Add(payment.AgentCommission)
+		result.Commissions[payment.Currency] = commission
+
+		order := match(payment, byPaymentID, byLiqpayOrderID, byOrderID)
+		switch {
+		case order == nil:
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindMissingInLedger, Payment: payment})
+			continue
+		case matched[order]:
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindDuplicatePayment, Order: order, Payment: payment})
+			continue
+		}
+
+		matched[order] = true
+		result.Matched++
+
+		if order.Currency != payment.Currency {
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindCurrencyMismatch, Order: order, Payment: payment})
+		} else if !order.Amount.Equal(payment.Amount) {
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindAmountMismatch, Order: order, Payment: payment})
+		}
+
+		if !statusEqual(order.Status, payment.Status) {
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindStatusMismatch, Order: order, Payment: payment})
+		}
+	}
+
+	for _, order := range ledger {
+		if !matched[order] {
+			result.Discrepancies = append(result.Discrepancies, Discrepancy{Kind: KindMissingInLiqPay, Order: order})
+		}
+	}
+
+	return result
+}
+
+// isDuplicate reports whether the order has the same payment_id, liqpay_order_id or order_id as an indexed order.
+func isDuplicate(order *Order, byPaymentID map[int64]*Order, byLiqpayOrderID, byOrderID map[string]*Order) bool {
+	if _, ok := byPaymentID[order.PaymentID]; ok && order.PaymentID != 0 {
+		return true
+	}
+	if _, ok := byLiqpayOrderID[order.LiqpayOrderID]; ok && order.LiqpayOrderID != "" {
+		return true
+	}
+	if _, ok := byOrderID[order.OrderID]; ok && order.OrderID != "" {
+		return true
+	}
+	return false
+}
+
+// match returns the order matching the payment by the first of payment_id, liqpay_order_id and order_id
+// that is set on both sides, or nil. An order whose identifier set on both sides differs from the payment's
+// does not match, even if a later identifier is equal.
+func match(payment *Payment, byPaymentID map[int64]*Order, byLiqpayOrderID, byOrderID map[string]*Order) *Order {
+	if order, ok := byPaymentID[payment.PaymentID]; ok && payment.PaymentID != 0 {
+		return order
+	}
+	if order, ok := byLiqpayOrderID[payment.LiqpayOrderID]; ok && payment.LiqpayOrderID != "" && !conflicts(payment, order) {
+		return order
+	}
+	if order, ok := byOrderID[payment.OrderID]; ok && payment.OrderID != "" && !conflicts(payment, order) {
+		return order
+	}
+	return nil
+}
+
+// conflicts reports whether the payment and the order have different payment_id or liqpay_order_id values set on both sides.
+func conflicts(payment *Payment, order *Order) bool {
+	if payment.PaymentID != 0 && order.PaymentID != 0 && payment.PaymentID != order.PaymentID {
+		return true
+	}
+	return payment.LiqpayOrderID != "" && order.LiqpayOrderID != "" && payment.LiqpayOrderID != order.LiqpayOrderID
+}
@@ -0,0 +1,176 @@
+package reconcile
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kabachoksolutions/liqpay"
+)
+
+func TestReconcile(t *testing.T) {
+	order := func(orderID string, amount string, status liqpay.Status) Order {
+		return Order{OrderID: orderID, Amount: liqpay.MustParseAmount(amount), Currency: liqpay.CurrencyUAH, Status: status}
+	}
+	payment := func(orderID string, amount string, status liqpay.Status) Payment {
+		return Payment{OrderID: orderID, Amount: liqpay.MustParseAmount(amount), Currency: liqpay.CurrencyUAH, Status: status}
+	}
+
+	type kindOf struct {
+		kind    Kind
+		orderID string
+	}
+
+	tests := []struct {
+		name        string
+		orders      []Order
+		payments    []Payment
+		wantMatched int
+		want        []kindOf
+	}{
+		{
+			name:        "matched",
+			orders:      []Order{order("a", "10", liqpay.StatusSuccess), order("b", "5.5", liqpay.StatusFailure)},
+			payments:    []Payment{payment("b", "5.50", liqpay.StatusError), payment("a", "10", liqpay.StatusWaitCompensation)},
+			wantMatched: 2,
+		},
+		{
+			name:     "missing in liqpay",
+			orders:   []Order{order("a", "10", liqpay.StatusSuccess)},
+			payments: nil,
+			want:     []kindOf{{KindMissingInLiqPay, "a"}},
+		},
+		{
+			name:     "missing in ledger",
+			orders:   nil,
+			payments: []Payment{payment("a", "10", liqpay.StatusSuccess)},
+			want:     []kindOf{{KindMissingInLedger, "a"}},
+		},
+		{
+			name:        "duplicate payment",
+			orders:      []Order{order("a", "10", liqpay.StatusSuccess)},
+			payments:    []Payment{payment("a", "10", liqpay.StatusSuccess), payment("a", "10", liqpay.StatusSuccess)},
+			wantMatched: 1,
+			want:        []kindOf{{KindDuplicatePayment, "a"}},
+		},
+		{
+			name:        "duplicate order",
+			orders:      []Order{order("a", "10", liqpay.StatusSuccess), order("a", "20", liqpay.StatusSuccess)},
+			payments:    []Payment{payment("a", "10", liqpay.StatusSuccess)},
+			wantMatched: 1,
+			want:        []kindOf{{KindDuplicateOrder, "a"}},
+		},
+		{
+			name: "duplicate order by payment id",
+			orders: []Order{
+				{OrderID: "a", PaymentID: 1, Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+				{OrderID: "b", PaymentID: 1, Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+			},
+			payments:    []Payment{{OrderID: "a", PaymentID: 1, Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess}},
+			wantMatched: 1,
+			want:        []kindOf{{KindDuplicateOrder, "b"}},
+		},
+		{
+			name:        "amount mismatch",
+			orders:      []Order{order("a", "10", liqpay.StatusSuccess)},
+			payments:    []Payment{payment("a", "10.01", liqpay.StatusSuccess)},
+			wantMatched: 1,
+			want:        []kindOf{{KindAmountMismatch, "a"}},
+		},
+		{
+			name:        "currency mismatch",
+			orders:      []Order{order("a", "10", liqpay.StatusSuccess)},
+			payments:    []Payment{{OrderID: "a", Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUSD, Status: liqpay.StatusSuccess}},
+			wantMatched: 1,
+			want:        []kindOf{{KindCurrencyMismatch, "a"}},
+		},
+		{
+			name:        "status mismatch",
+			orders:      []Order{order("a", "10", liqpay.StatusSuccess)},
+			payments:    []Payment{payment("a", "10", liqpay.StatusReversed)},
+			wantMatched: 1,
+			want:        []kindOf{{KindStatusMismatch, "a"}},
+		},
+		{
+			name: "matched by liqpay order id before order id",
+			orders: []Order{
+				{OrderID: "a", LiqpayOrderID: "LP1", Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+				{OrderID: "b", Amount: liqpay.MustParseAmount("20"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+			},
+			payments:    []Payment{{OrderID: "b", LiqpayOrderID: "LP1", Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess}},
+			wantMatched: 1,
+			want:        []kindOf{{KindMissingInLiqPay, "b"}},
+		},
+		{
+			name: "different payment ids with the same order id",
+			orders: []Order{
+				{OrderID: "a", PaymentID: 1, Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+			},
+			payments: []Payment{{OrderID: "a", PaymentID: 2, Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess}},
+			want:     []kindOf{{KindMissingInLedger, "a"}, {KindMissingInLiqPay, "a"}},
+		},
+		{
+			name: "different liqpay order ids with the same order id",
+			orders: []Order{
+				{OrderID: "a", LiqpayOrderID: "LP1", Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+			},
+			payments: []Payment{{OrderID: "a", LiqpayOrderID: "LP2", Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess}},
+			want:     []kindOf{{KindMissingInLedger, "a"}, {KindMissingInLiqPay, "a"}},
+		},
+		{
+			name: "payment id unknown in the ledger falls back to order id",
+			orders: []Order{
+				{OrderID: "a", Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess},
+			},
+			payments:    []Payment{{OrderID: "a", PaymentID: 2, Amount: liqpay.MustParseAmount("10"), Currency: liqpay.CurrencyUAH, Status: liqpay.StatusSuccess}},
+			wantMatched: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Reconcile(slices.Values(tt.orders), slices.Values(tt.payments), nil)
+
+			if result.Matched != tt.wantMatched {
+				t.Errorf("Matched = %d, want %d", result.Matched, tt.wantMatched)
+			}
+
+			var got []kindOf
+			for _, d := range result.Discrepancies {
+				k := kindOf{kind: d.Kind}
+				if d.Order != nil {
+					k.orderID = d.Order.OrderID
+				} else {
+					k.orderID = d.Payment.OrderID
+				}
+				got = append(got, k)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Discrepancies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileCommissions(t *testing.T) {
+	payments := []Payment{
+		{OrderID: "a", Currency: liqpay.CurrencyUAH, SenderCommission: liqpay.MustParseAmount("0.1"), ReceiverCommission: liqpay.MustParseAmount("1.5")},
+		{OrderID: "b", Currency: liqpay.CurrencyUAH, ReceiverCommission: liqpay.MustParseAmount("0.75"), AgentCommission: liqpay.MustParseAmount("0.05")},
+		{OrderID: "c", Currency: liqpay.CurrencyUSD, ReceiverCommission: liqpay.MustParseAmount("0.02")},
+	}
+
+	result := Reconcile(slices.Values([]Order(nil)), slices.Values(payments), nil)
+
+	tests := []struct {
+		currency liqpay.Currency
+		want     string
+	}{
+		{currency: liqpay.CurrencyUAH, want: "2.4"},
+		{currency: liqpay.CurrencyUSD, want: "0.02"},
+		{currency: liqpay.CurrencyEUR, want: "0"},
+	}
+	for _, tt := range tests {
+		if got := result.Commissions[tt.currency].Total(); got.String() != tt.want {
+			t.Errorf("Commissions[%s].Total() = %s, want %s", tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package liqpay
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Reports retrieves the payments archive for the period from..to.
+func (c client) Reports(from, to time.Time) (*ReportsResponse, error) {
+	return c.ReportsContext(context.Background(), from, to)
+}
+
+// ReportsContext retrieves the payments archive for the period from..to using the provided context.
+func (c client) ReportsContext(ctx context.Context, from, to time.Time) (*ReportsResponse, error) {
+	data, err := newReportsRequest(from, to, ReportFormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &ReportsResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ReportsCSV retrieves the payments archive for the period from..to in CSV format.
+func (c client) ReportsCSV(from, to time.Time) ([]byte, error) {
+	return c.ReportsCSVContext(context.Background(), from, to)
+}
+
+// ReportsCSVContext retrieves the payments archive for the period from..to in CSV format using the provided context.
+func (c client) ReportsCSVContext(ctx context.Context, from, to time.Time) ([]byte, error) {
+	data, err := newReportsRequest(from, to, ReportFormatCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var v []byte
+	if err := c.sendServerRequest(req, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ReportsCompensation retrieves the compensation register for the date.
+// Only the calendar date of date in its location is used.
+func (c client) ReportsCompensation(date time.Time) (*CompensationReportResponse, error) {
+	return c.ReportsCompensationContext(context.Background(), date)
+}
+
+// ReportsCompensationContext retrieves the compensation register for the date using the provided context.
+// Only the calendar date of date in its location is used.
+func (c client) ReportsCompensationContext(ctx context.Context, date time.Time) (*CompensationReportResponse, error) {
+	data, err := newCompensationReportRequest(date, ReportFormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &CompensationReportResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ReportsCompensationCSV retrieves the compensation register for the date in CSV format.
+// Only the calendar date of date in its location is used.
+func (c client) ReportsCompensationCSV(date time.Time) ([]byte, error) {
+	return c.ReportsCompensationCSVContext(context.Background(), date)
+}
+
+// ReportsCompensationCSVContext retrieves the compensation register for the date in CSV format using the provided context.
+// Only the calendar date of date in its location is used.
+func (c client) ReportsCompensationCSVContext(ctx context.Context, date time.Time) ([]byte, error) {
+	data, err := newCompensationReportRequest(date, ReportFormatCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var v []byte
+	if err := c.sendServerRequest(req, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func newReportsRequest(from, to time.Time, format ReportFormat) (*ReportsRequest, error) {
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return nil, errors.New("liqpay client: report period start must be before its end")
+	}
+
+	return &ReportsRequest{
+		Action:     ActionReports,
+		DateFrom:   from.UnixMilli(),
+		DateTo:     to.UnixMilli(),
+		RespFormat: format,
+	}, nil
+}
+
+func newCompensationReportRequest(date time.Time, format ReportFormat) (*CompensationReportRequest, error) {
+	if date.IsZero() {
+		return nil, errors.New("liqpay client: compensation date is required")
+	}
+
+	return &CompensationReportRequest{
+		Action:     ActionReportsCompensation,
+		Date:       date.Format(time.DateOnly),
+		RespFormat: format,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package liqpay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// requestData decodes the data parameter of a recorded server request body.
+func requestData(t *testing.T, body string) map[string]any {
+	t.Helper()
+
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		t.Fatalf("request body is not a form: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(form.Get("data"))
+	if err != nil {
+		t.Fatalf("request data is not base64: %v", err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(decoded, &data); err != nil {
+		t.Fatalf("request data is not json: %v", err)
+	}
+	return data
+}
+
+func TestReportsRequest(t *testing.T) {
+	kyiv := time.FixedZone("EEST", 3*60*60)
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, kyiv)
+	to := time.Date(2024, 5, 2, 0, 0, 0, 500e6, kyiv)
+	date := time.Date(2024, 5, 1, 1, 30, 0, 0, kyiv) // 2024-04-30 in UTC
+
+	tests := []struct {
+		name string
+		call func(c Client) error
+		want map[string]any
+	}{
+		{
+			name: "reports",
+			call: func(c Client) error { _, err := c.Reports(from, to); return err },
+			want: map[string]any{"action": "reports", "date_from": 1714510800000.0, "date_to": 1714597200500.0, "resp_format": "json"},
+		},
+		{
+			name: "reports csv",
+			call: func(c Client) error { _, err := c.ReportsCSV(from, to); return err },
+			want: map[string]any{"action": "reports", "date_from": 1714510800000.0, "date_to": 1714597200500.0, "resp_format": "csv"},
+		},
+		{
+			name: "compensation date in its location",
+			call: func(c Client) error { _, err := c.ReportsCompensation(date); return err },
+			want: map[string]any{"action": "reports_compensation", "date": "2024-05-01", "resp_format": "json"},
+		},
+		{
+			name: "compensation csv",
+			call: func(c Client) error { _, err := c.ReportsCompensationCSV(date.UTC()); return err },
+			want: map[string]any{"action": "reports_compensation", "date": "2024-04-30", "resp_format": "csv"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &scriptedTransport{replies: []func() (*http.Response, error){reply(http.StatusOK, `{"result":"success","data":[]}`)}}
+			c := NewClient(NewConfig("public", "private", false), &http.Client{Transport: transport})
+
+			if err := tt.call(c); err != nil {
+				t.Fatalf("call error = %v", err)
+			}
+
+			data := requestData(t, transport.bodies[0])
+			for key, want := range tt.want {
+				if got := data[key]; got != want {
+					t.Errorf("data[%s] = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReportsCSVReturnsBody(t *testing.T) {
+	const body = "order_id,amount\norder-1,100\n"
+
+	transport := &scriptedTransport{replies: []func() (*http.Response, error){
+		func() (*http.Response, error) {
+			resp, err := reply(http.StatusOK, body)()
+			resp.Header.Set("Content-Type", "text/csv")
+			return resp, err
+		},
+	}}
+	c := NewClient(NewConfig("public", "private", false), &http.Client{Transport: transport})
+
+	got, err := c.ReportsCSV(time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("ReportsCSV() error = %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("ReportsCSV() = %q, want %q", got, body)
+	}
+}
+
+func TestReportsValidation(t *testing.T) {
+	c := NewClient(NewConfig("public", "private", false), nil)
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "zero start", call: func() error { _, err := c.Reports(time.Time{}, now); return err }},
+		{name: "zero end", call: func() error { _, err := c.ReportsCSV(now, time.Time{}); return err }},
+		{name: "start after end", call: func() error { _, err := c.Reports(now, now.Add(-time.Hour)); return err }},
+		{name: "empty period", call: func() error { _, err := c.Reports(now, now); return err }},
+		{name: "zero compensation date", call: func() error { _, err := c.ReportsCompensation(time.Time{}); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil {
+				t.Error("call error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package liqpay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxResponseSize is the maximum size of a LiqPay API response body.
+const maxResponseSize = 32 << 20
+
+// ResponseError represents a LiqPay API response that could not be decoded,
+// e.g. a proxy error page, a truncated body or a body of unexpected shape.
+type ResponseError struct {
+	StatusCode  int    // HTTP status code
+	ContentType string // Content-Type header of the response
+	Body        []byte // Raw response body, truncated to 32 MiB
+	Err         error  // Underlying error
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("liqpay client: invalid response (http status %d, content type %q): %v", e.StatusCode, e.ContentType, e.Err)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// responseEnvelope is the part of every LiqPay API response that describes the request outcome.
+type responseEnvelope struct {
+	Status         jsonString `json:"status"`
+	Result         jsonString `json:"result"`
+	ErrCode        jsonString `json:"err_code"`
+	ErrDescription jsonString `json:"err_description"`
+	ErrErc         jsonString `json:"err_erc"`
+}
+
+// jsonString decodes a JSON string, number or boolean into its string form, and null into an empty string.
+// LiqPay returns some codes either as strings or as numbers.
+type jsonString string
+
+func (s *jsonString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*s = ""
+	case len(data) > 0 && data[0] == '"':
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = jsonString(str)
+	case len(data) > 0 && (data[0] == '{' || data[0] == '['):
+		return fmt.Errorf("unexpected json value %.32s", data)
+	default:
+		*s = jsonString(data)
+	}
+	return nil
+}
+
+// readResponse reads the response body, limited to maxResponseSize.
+func readResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxResponseSize {
+		return body[:maxResponseSize], fmt.Errorf("response body exceeds %d bytes", maxResponseSize)
+	}
+	return body, nil
+}
+
+// isJSONContentType reports whether the content type may hold a JSON body.
+// LiqPay does not always set the content type, so an empty and a text/plain one are accepted too.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "application/json",
+		mediaType == "text/json",
+		mediaType == "text/plain",
+		strings.HasSuffix(mediaType, "+json"):
+		return true
+	}
+	return false
+}
+
+// decodeServerResponse decodes the body of a LiqPay API response into v and returns an *APIError
+// if the response reports a failed request. It never panics on malformed bodies.
+func decodeServerResponse(resp *http.Response, body []byte, v any) error {
+	contentType := resp.Header.Get("Content-Type")
+	newResponseError := func(err error) error {
+		return &ResponseError{StatusCode: resp.StatusCode, ContentType: contentType, Body: body, Err: err}
+	}
+
+	trimmed := bytes.TrimSpace(body)
+
+	// A raw response, e.g. a CSV report, is returned as is unless it is a JSON error.
+	if raw, ok := v.(*[]byte); ok && (len(trimmed) == 0 || trimmed[0] != '{') {
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return newResponseError(fmt.Errorf("unexpected http status %s", resp.Status))
+		}
+		*raw = body
+		return nil
+	}
+
+	if !isJSONContentType(contentType) {
+		return newResponseError(errors.New("unexpected content type"))
+	}
+
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return newResponseError(errors.New("response body is not a json object"))
+	}
+
+	var envelope responseEnvelope
+	if err := json.Unmarshal(trimmed, &envelope); err != nil {
+		return newResponseError(err)
+	}
+
+	if envelope.Status == "error" || envelope.Status == "failure" || envelope.Result == "error" {
+		if v != nil {
+			// The response is decoded on a best-effort basis, the API error takes precedence.
+			_ = json.Unmarshal(trimmed, v)
+		}
+
+		status := envelope.Status
+		if status == "" {
+			status = envelope.Result
+		}
+
+		return &APIError{
+			Status: string(status),
+			Code:   string(envelope.ErrCode),
+			Desc:   string(envelope.ErrDescription),
+			Erc:    string(envelope.ErrErc),
+			Body:   string(body),
+		}
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(fmt.Errorf("unexpected http status %s", resp.Status))
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*v = body
+		return nil
+	}
+
+	if err := json.Unmarshal(trimmed, v); err != nil {
+		return newResponseError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// APIError values are compared by callers, so it must stay comparable.
+var _ = APIError{} == APIError{}
+
+func TestDecodeServerResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		contentType  string
+		body         string
+		wantStatus   Status
+		wantAPIError *APIError
+		wantRespErr  bool
+	}{
+		{
+			name:        "success",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"status":"success","order_id":"order-1","amount":7.34}`,
+			wantStatus:  StatusSuccess,
+		},
+		{
+			name:       "missing content type",
+			statusCode: http.StatusOK,
+			body:       ` {"status":"success"}`,
+			wantStatus: StatusSuccess,
+		},
+		{
+			name:         "api error with numeric codes",
+			statusCode:   http.StatusOK,
+			contentType:  "application/json",
+			body:         `{"result":"error","status":"failure","err_code":9854,"err_erc":9854,"err_description":"declined"}`,
+			wantStatus:   StatusFailure,
+			wantAPIError: &APIError{Status: "failure", Code: "9854", Erc: "9854", Desc: "declined"},
+		},
+		{
+			name:         "api error on non-2xx status",
+			statusCode:   http.StatusBadRequest,
+			contentType:  "application/json",
+			body:         `{"result":"error","err_code":"payment_not_found","err_description":"payment not found"}`,
+			wantAPIError: &APIError{Status: "error", Code: "payment_not_found", Desc: "payment not found"},
+		},
+		{
+			name:        "success body on non-2xx status",
+			statusCode:  http.StatusForbidden,
+			contentType: "application/json",
+			body:        `{"status":"success"}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "html page",
+			statusCode:  http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			body:        `<html><body>Bad gateway</body></html>`,
+			wantRespErr: true,
+		},
+		{
+			name:        "html body without content type",
+			statusCode:  http.StatusOK,
+			body:        `<html><body>Bad gateway</body></html>`,
+			wantRespErr: true,
+		},
+		{
+			name:        "truncated json",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"status":"succ`,
+			wantRespErr: true,
+		},
+		{
+			name:        "empty body",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			wantRespErr: true,
+		},
+		{
+			name:        "json array",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `[{"status":"success"}]`,
+			wantRespErr: true,
+		},
+		{
+			name:        "object in place of status",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"status":{"code":"success"}}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "field of unexpected type",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"status":"success","amount":"abc"}`,
+			wantStatus:  StatusSuccess,
+			wantRespErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.statusCode,
+				Status:     http.StatusText(tt.statusCode),
+				Header:     http.Header{},
+			}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+
+			v := &StatusResponse{}
+			err := decodeServerResponse(resp, []byte(tt.body), v)
+
+			var apiErr *APIError
+			var respErr *ResponseError
+			switch {
+			case tt.wantAPIError != nil:
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("decodeServerResponse() error = %v, want APIError", err)
+				}
+				want := *tt.wantAPIError
+				want.Body = tt.body
+				if *apiErr != want {
+					t.Errorf("decodeServerResponse() error = %#v, want %#v", *apiErr, want)
+				}
+			case tt.wantRespErr:
+				if !errors.As(err, &respErr) {
+					t.Fatalf("decodeServerResponse() error = %v, want ResponseError", err)
+				}
+				if string(respErr.Body) != tt.body || respErr.StatusCode != tt.statusCode {
+					t.Errorf("ResponseError = %d %q, want %d %q", respErr.StatusCode, respErr.Body, tt.statusCode, tt.body)
+				}
+			case err != nil:
+				t.Fatalf("decodeServerResponse() error = %v", err)
+			}
+
+			if v.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", v.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDecodeServerResponseRaw(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		body         string
+		wantErr      bool
+		wantAPIError bool
+	}{
+		{name: "csv", statusCode: http.StatusOK, body: "order_id,amount\norder-1,7.34\n"},
+		{name: "csv on non-2xx status", statusCode: http.StatusBadGateway, body: "bad gateway", wantErr: true},
+		{name: "json error", statusCode: http.StatusOK, body: `{"result":"error","err_code":"err_access"}`, wantErr: true, wantAPIError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Status: http.StatusText(tt.statusCode), Header: http.Header{}}
+
+			var v []byte
+			err := decodeServerResponse(resp, []byte(tt.body), &v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeServerResponse() error = %v, want error %t", err, tt.wantErr)
+			}
+			if got := ErrorRefersToAPI(err); got != tt.wantAPIError {
+				t.Errorf("ErrorRefersToAPI() = %t, want %t", got, tt.wantAPIError)
+			}
+			if !tt.wantErr && string(v) != tt.body {
+				t.Errorf("body = %q, want %q", v, tt.body)
+			}
+		})
+	}
+}
+
+func TestDecodeNumericCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		v    any
+		want func(v any) bool
+	}{
+		{
+			name: "status response",
+			body: `{"status":"success","mpi_eci":7,"authcode_debit":123456,"rrn_debit":1234567890,"sender_card_country":804}`,
+			v:    &StatusResponse{},
+			want: func(v any) bool {
+				s := v.(*StatusResponse)
+				return s.MPIECI == "7" && s.AuthCodeDebit == "123456" && s.RRNDebit == "1234567890" && s.SenderCardCountry == 804
+			},
+		},
+		{
+			name: "status response with string codes",
+			body: `{"status":"success","mpi_eci":"5","authcode_debit":"0A1B2C"}`,
+			v:    &StatusResponse{},
+			want: func(v any) bool {
+				s := v.(*StatusResponse)
+				return s.MPIECI == "5" && s.AuthCodeDebit == "0A1B2C"
+			},
+		},
+		{
+			name: "callback",
+			body: `{"status":"failure","mpi_eci":7,"sender_card_country":804,"err_code":90,"err_erc":"90","rrn_credit":42}`,
+			v:    &Callback{},
+			want: func(v any) bool {
+				c := v.(*Callback)
+				return c.MpiEci == 7 && c.SenderCardCountry == "804" && c.ErrCode == "90" && c.ErrErc == "90" && c.RRNCredit == "42"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.body), tt.v); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !tt.want(tt.v) {
+				t.Errorf("decoded = %+v", tt.v)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package liqpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// RetryPolicy describes how failed server-server requests are retried.
+//
+// By default only actions that do not move money (see IsIdempotentAction) are retried,
+// and only for transient failures (see IsRetryableError). Money-moving actions such as
+// refund are never retried by the default policy, since a network failure does not tell
+// whether LiqPay has already processed the request.
+type RetryPolicy struct {
+	MaxAttempts    int                                 // Maximum number of attempts, including the first one. Values below 2 disable retries
+	InitialBackoff time.Duration                       // Delay before the first retry
+	MaxBackoff     time.Duration                       // Upper bound of the delay between retries
+	Multiplier     float64                             // Factor by which the delay grows after each retry. Defaults to 2
+	Jitter         float64                             // Fraction of the delay that is randomized, from 0 to 1
+	RetryOn        func(action Action, err error) bool // Decides whether a failed attempt is retried. Defaults to DefaultRetryOn
+}
+
+// DefaultRetryPolicy returns a retry policy with 3 attempts and exponential backoff from 200ms to 2s with 20% jitter.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// DefaultRetryOn retries transient failures of actions that are safe to repeat.
+func DefaultRetryOn(action Action, err error) bool {
+	return IsIdempotentAction(action) && IsRetryableError(err)
+}
+
+// IsIdempotentAction reports whether the action can be repeated without moving money twice.
+func IsIdempotentAction(action Action) bool {
+	switch action {
+	case ActionStatus, ActionReports, ActionReportsCompensation:
+		return true
+	}
+	return false
+}
+
+// IsRetryableError reports whether the error is transient and the request may succeed if repeated:
+// network failures, 5xx and 429 HTTP responses, and API errors for which APIError.Retryable
+// or, for card token payments, TokenError.Retryable reports true.
+// Context cancellation and deadline errors are never retryable.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var tokenErr *TokenError
+	if errors.As(err, &tokenErr) {
+		return tokenErr.Retryable()
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// retryOn returns whether the failed attempt should be retried according to the policy.
+func (p *RetryPolicy) retryOn(action Action, err error) bool {
+	if p.RetryOn != nil {
+		return p.RetryOn(action, err)
+	}
+	return DefaultRetryOn(action, err)
+}
+
+// backoff returns the delay before the given retry, starting from 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// wait sleeps before the given retry or returns early if the context is done.
+func (p *RetryPolicy) wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(p.backoff(retry))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return contextError(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryRequest returns a copy of the request with a fresh body for another attempt.
+func retryRequest(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("liqpay client: failed to rewind request body: %w", err)
+		}
+		retry.Body = body
+	}
+
+	return retry, nil
+}
+
+// newResponse returns a pointer to a fresh value of the type the response v points to.
+func newResponse(v any) any {
+	return reflect.New(reflect.TypeOf(v).Elem()).Interface()
+}
+
+// setResponse replaces the value the response v points to with the value decoded by an attempt.
+func setResponse(v, attempt any) {
+	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(attempt).Elem())
+}
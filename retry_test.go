@@ -0,0 +1,236 @@
+package liqpay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		want   time.Duration
+	}{
+		{name: "first retry", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 3}, retry: 1, want: 100 * time.Millisecond},
+		{name: "third retry", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 3}, retry: 3, want: 900 * time.Millisecond},
+		{name: "default multiplier", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond}, retry: 3, want: 400 * time.Millisecond},
+		{name: "capped", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 250 * time.Millisecond}, retry: 3, want: 250 * time.Millisecond},
+		{name: "capped after many retries", policy: RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 2 * time.Second}, retry: 1000, want: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.retry); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter float64
+		lo, hi time.Duration
+	}{
+		{name: "20%", jitter: 0.2, lo: 80 * time.Millisecond, hi: 120 * time.Millisecond},
+		{name: "above 1 is capped", jitter: 5, lo: 0, hi: 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: tt.jitter}
+
+			seen := map[time.Duration]bool{}
+			for range 200 {
+				got := policy.backoff(1)
+				if got < tt.lo || got > tt.hi {
+					t.Fatalf("backoff(1) = %v, want within [%v, %v]", got, tt.lo, tt.hi)
+				}
+				seen[got] = true
+			}
+			if len(seen) < 2 {
+				t.Errorf("backoff(1) returned the same delay 200 times, want it randomized")
+			}
+		})
+	}
+}
+
+func TestDefaultRetryOn(t *testing.T) {
+	networkErr := &url.Error{Op: "Post", URL: ServerServerURL, Err: errors.New("connection reset")}
+
+	tests := []struct {
+		name   string
+		action Action
+		err    error
+		want   bool
+	}{
+		{name: "status network error", action: ActionStatus, err: networkErr, want: true},
+		{name: "reports 503", action: ActionReports, err: &HTTPError{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{name: "compensation 429", action: ActionReportsCompensation, err: &HTTPError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "status payment_processing", action: ActionStatus, err: &APIError{Code: string(NonFinancialPaymentProcessing)}, want: true},
+		{name: "status 400", action: ActionStatus, err: &HTTPError{StatusCode: http.StatusBadRequest}},
+		{name: "status final api error", action: ActionStatus, err: &APIError{Code: string(NonFinancialAccessError)}},
+		{name: "status deadline", action: ActionStatus, err: &url.Error{Op: "Post", URL: ServerServerURL, Err: context.DeadlineExceeded}},
+		{name: "refund network error", action: ActionRefund, err: networkErr},
+		{name: "pay network error", action: ActionPay, err: networkErr},
+		{name: "hold completion 503", action: ActionHoldCompletion, err: &HTTPError{StatusCode: http.StatusServiceUnavailable}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryOn(tt.action, tt.err); got != tt.want {
+				t.Errorf("DefaultRetryOn(%s, %v) = %t, want %t", tt.action, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIdempotentAction(t *testing.T) {
+	tests := []struct {
+		action Action
+		want   bool
+	}{
+		{action: ActionStatus, want: true},
+		{action: ActionReports, want: true},
+		{action: ActionReportsCompensation, want: true},
+		{action: ActionPay},
+		{action: ActionRefund},
+		{action: ActionHoldCompletion},
+		{action: ActionPayToken},
+		{action: ActionConfirm},
+	}
+
+	for _, tt := range tests {
+		if got := IsIdempotentAction(tt.action); got != tt.want {
+			t.Errorf("IsIdempotentAction(%s) = %t, want %t", tt.action, got, tt.want)
+		}
+	}
+}
+
+// scriptedTransport replies to each request with the next scripted response and records the request bodies.
+type scriptedTransport struct {
+	replies []func() (*http.Response, error)
+	bodies  []string
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.bodies = append(s.bodies, string(body))
+
+	reply := s.replies[min(len(s.bodies), len(s.replies))-1]
+	return reply()
+}
+
+func reply(code int, body string) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		return &http.Response{
+			StatusCode: code,
+			Status:     http.StatusText(code),
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+func TestSendServerRequestRetries(t *testing.T) {
+	networkErr := func() (*http.Response, error) { return nil, errors.New("connection reset") }
+	success := reply(http.StatusOK, `{"status":"success","order_id":"order-1"}`)
+
+	tests := []struct {
+		name         string
+		call         func(c Client) (any, error)
+		replies      []func() (*http.Response, error)
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "status retried after a network error",
+			call:         func(c Client) (any, error) { return c.Status("order-1") },
+			replies:      []func() (*http.Response, error){networkErr, success},
+			wantAttempts: 2,
+		},
+		{
+			name:         "status retried after 503",
+			call:         func(c Client) (any, error) { return c.Status("order-1") },
+			replies:      []func() (*http.Response, error){reply(http.StatusServiceUnavailable, "unavailable"), reply(http.StatusBadGateway, "bad gateway"), success},
+			wantAttempts: 3,
+		},
+		{
+			name: "status gives up after max attempts",
+			call: func(c Client) (any, error) { return c.Status("order-1") },
+			replies: []func() (*http.Response, error){
+				reply(http.StatusServiceUnavailable, "unavailable"),
+			},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "refund is never retried",
+			call:         func(c Client) (any, error) { return c.Refund("order-1", MustParseAmount("10")) },
+			replies:      []func() (*http.Response, error){networkErr, success},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name: "pay by card is never retried",
+			call: func(c Client) (any, error) {
+				return c.PayByCard(&CardPaymentRequest{Amount: MustParseAmount("10"), Card: "4242424242424242", Currency: CurrencyUAH, IP: "127.0.0.1", OrderID: "order-1"})
+			},
+			replies:      []func() (*http.Response, error){reply(http.StatusServiceUnavailable, "unavailable"), success},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &scriptedTransport{replies: tt.replies}
+			cfg := NewConfig("public", "private", false)
+			cfg.RetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+			c := NewClient(cfg, &http.Client{Transport: transport})
+
+			_, err := tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("call error = %v, want error %t", err, tt.wantErr)
+			}
+
+			if len(transport.bodies) != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", len(transport.bodies), tt.wantAttempts)
+			}
+			for i, body := range transport.bodies {
+				if body == "" || body != transport.bodies[0] {
+					t.Errorf("attempt %d body = %q, want %q", i+1, body, transport.bodies[0])
+				}
+			}
+		})
+	}
+}
+
+func TestSendServerRequestRetryDecodesFreshResponse(t *testing.T) {
+	transport := &scriptedTransport{replies: []func() (*http.Response, error){
+		reply(http.StatusOK, `{"result":"error","status":"error","err_code":"payment_processing","order_id":"stale","amount":5,"description":"first attempt"}`),
+		reply(http.StatusOK, `{"status":"success","order_id":"order-1"}`),
+	}}
+	cfg := NewConfig("public", "private", false)
+	cfg.RetryPolicy = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	c := NewClient(cfg, &http.Client{Transport: transport})
+
+	v, err := c.Status("order-1")
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if v.Status != StatusSuccess || v.OrderID != "order-1" || !v.Amount.IsZero() || v.Description != "" {
+		t.Errorf("Status() = %+v, want only the fields of the second attempt", v)
+	}
+}
@@ -0,0 +1,64 @@
+package liqpay
+
+import (
+	"context"
+	"fmt"
+)
+
+// SplitPayment performs a server-server card payment split between several recipients.
+// The split rules amounts must sum up to the payment amount, otherwise the request is not sent
+// and an APIError with the err_split_amount code is returned.
+func (c client) SplitPayment(data *SplitPaymentRequest) (*StatusResponse, error) {
+	return c.SplitPaymentContext(context.Background(), data)
+}
+
+// SplitPaymentContext performs a server-server split payment using the provided context.
+func (c client) SplitPaymentContext(ctx context.Context, data *SplitPaymentRequest) (*StatusResponse, error) {
+	data.Action = ActionPaySplit
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := validateSplitRules(data.Amount, data.SplitRules); err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &StatusResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// validateSplitRules checks that the split rules amounts sum up to the payment amount.
+func validateSplitRules(amount Amount, rules []SplitRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var total Amount
+	for _, rule := range rules {
+		total = total.Add(rule.Amount)
+	}
+
+	if !total.Equal(amount) {
+		return &APIError{
+			Status: "error",
+			Code:   string(NonFinancialSplitAmountMismatch),
+			Desc:   fmt.Sprintf("split rules amounts sum %s does not match payment amount %s", total, amount),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package liqpay
+
+// Payment status lifecycle.
+//
+// The table below lists the typical transitions of a payment status. Statuses marked
+// as final do not change anymore. Statuses marked with * are final as well (see Status.IsFinal),
+// as the payment itself is complete, but a later merchant action still changes them:
+// a refund reverses a successful payment, and unsubscribing ends a subscription.
+//
+//	From                         To
+//	prepared                     processing, <verification>, success, failure, error
+//	<verification>               processing, <verification>, wait_secure, wait_accept, hold_wait, success, failure, error
+//	processing                   <verification>, wait_secure, wait_accept, hold_wait, wait_compensation, success, failure, error
+//	wait_qr, wait_sender         processing, success, failure, error
+//	invoice_wait, cash_wait      processing, success, failure, error
+//	wait_secure                  wait_accept, wait_compensation, success, failure, reversed
+//	wait_accept, wait_lc         wait_compensation, success, failure, reversed
+//	hold_wait                    success (hold completion), reversed (hold cancellation), failure
+//	wait_compensation            success, reversed
+//	success*                     wait_reserve, reversed (refund)
+//	wait_reserve                 reversed
+//	subscribed*                  unsubscribed
+//	try_again                    prepared, processing, <verification>, failure, error
+//	error, failure, reversed,    (final)
+//	unsubscribed
+//
+// <verification> stands for any status that requires customer confirmation:
+// 3ds_verify, captcha_verify, cvv_verify, dcc_verify, ivr_verify, otp_verify, password_verify,
+// phone_verify, pin_verify, receiver_verify, sender_verify, senderapp_verify,
+// p24_verify and mp_verify.
+
+// IsFinal reports whether the status is final: error, failure, reversed, subscribed, success or unsubscribed.
+// A success payment may still be reversed by a refund, and a subscribed one may be unsubscribed later.
+func (s Status) IsFinal() bool {
+	switch s {
+	case StatusError, StatusFailure, StatusReversed, StatusSubscribed, StatusSuccess, StatusUnsubscribed:
+		return true
+	}
+	return false
+}
+
+// IsSuccessful reports whether the payment was completed successfully: success, subscribed or wait_compensation.
+func (s Status) IsSuccessful() bool {
+	switch s {
+	case StatusSuccess, StatusSubscribed, StatusWaitCompensation:
+		return true
+	}
+	return false
+}
+
+// IsFailed reports whether the payment failed: error or failure.
+func (s Status) IsFailed() bool {
+	return s == StatusError || s == StatusFailure
+}
+
+// IsPending reports whether the payment is not in a final status yet.
+func (s Status) IsPending() bool {
+	return !s.IsFinal()
+}
+
+// RequiresCustomerAction reports whether the payment is waiting for the customer:
+// a confirmation (3DS, OTP, CVV, DCC, etc.), a QR code scan, or an invoice or cash payment.
+func (s Status) RequiresCustomerAction() bool {
+	switch s {
+	case Status3DSVerify, StatusCaptchaVerify, StatusCVVVerify, StatusDCCVerify, StatusIVRVerify, StatusOTPVerify,
+		StatusPasswordVerify, StatusPhoneVerify, StatusPINVerify, StatusReceiverVerify, StatusSenderVerify,
+		StatusSenderAppVerify, StatusWaitQR, StatusWaitSender, StatusP24Verify, StatusMPVerify,
+		StatusInvoiceWait, StatusCashWait:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package liqpay
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TimeLayout is the layout of LiqPay dates, e.g. 2016-04-24 00:00:00. LiqPay dates are in UTC.
+const TimeLayout = "2006-01-02 15:04:05"
+
+// Time is a LiqPay date.
+//
+// It is decoded from epoch milliseconds, as LiqPay returns dates in responses and callbacks,
+// or from a string in TimeLayout. It is encoded in TimeLayout in UTC, as LiqPay expects dates in requests.
+type Time struct {
+	time.Time
+}
+
+// NewTime creates a new Time from t.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// MarshalJSON encodes the time as a string in TimeLayout in UTC. The zero time is encoded as null.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.UTC().Format(TimeLayout))), nil
+}
+
+// UnmarshalJSON decodes the time from epoch milliseconds, as a number or a string,
+// or from a string in TimeLayout in UTC. Null, empty strings and 0 are decoded as the zero time.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Time{}
+		return nil
+	}
+
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	if s == "" {
+		*t = Time{}
+		return nil
+	}
+
+	if millis, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*t = Time{}
+		if millis != 0 {
+			t.Time = time.UnixMilli(millis).UTC()
+		}
+		return nil
+	}
+
+	parsed, err := time.ParseInLocation(TimeLayout, s, time.UTC)
+	if err != nil {
+		return fmt.Errorf("liqpay: invalid time %s", data)
+	}
+	t.Time = parsed
+	return nil
+}
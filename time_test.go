@@ -0,0 +1,160 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeUnmarshalJSON(t *testing.T) {
+	want := time.Date(2016, 4, 24, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: `1461493815000`, want: want},
+		{in: `"1461493815000"`, want: want},
+		{in: `1461493815123`, want: want.Add(123 * time.Millisecond)},
+		{in: `"2016-04-24 10:30:15"`, want: want},
+		{in: `null`},
+		{in: `""`},
+		{in: `0`},
+		{in: `"2016-04-24"`, wantErr: true},
+		{in: `"2016-04-24T10:30:15Z"`, wantErr: true},
+		{in: `"yesterday"`, wantErr: true},
+		{in: `1.5`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := NewTime(time.Now())
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, want error %t", tt.in, err, tt.wantErr)
+			}
+			if err == nil && !got.Time.Equal(tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got.Time, tt.want)
+			}
+			if err == nil && !got.IsZero() && got.Location() != time.UTC {
+				t.Errorf("Unmarshal(%s) location = %v, want UTC", tt.in, got.Location())
+			}
+		})
+	}
+}
+
+func TestTimeMarshalJSON(t *testing.T) {
+	kyiv := time.FixedZone("EEST", 3*60*60)
+
+	tests := []struct {
+		name string
+		in   Time
+		want string
+	}{
+		{name: "utc", in: NewTime(time.Date(2016, 4, 24, 10, 30, 15, 0, time.UTC)), want: `"2016-04-24 10:30:15"`},
+		{name: "converted to utc", in: NewTime(time.Date(2016, 4, 24, 13, 30, 15, 0, kyiv)), want: `"2016-04-24 10:30:15"`},
+		{name: "milliseconds are dropped", in: NewTime(time.Date(2016, 4, 24, 10, 30, 15, 999e6, time.UTC)), want: `"2016-04-24 10:30:15"`},
+		{name: "zero", in: Time{}, want: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeInModels(t *testing.T) {
+	want := time.Date(2016, 4, 24, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		body string
+		v    any
+		got  func(v any) Time
+	}{
+		{
+			name: "status response create_date",
+			body: `{"create_date":1461493815000}`,
+			v:    &StatusResponse{},
+			got:  func(v any) Time { return v.(*StatusResponse).CreateDate },
+		},
+		{
+			name: "subscription response create_date",
+			body: `{"create_date":1461493815000}`,
+			v:    &SubscriptionResponse{},
+			got:  func(v any) Time { return v.(*SubscriptionResponse).CreateDate },
+		},
+		{
+			name: "callback end_date",
+			body: `{"end_date":"1461493815000"}`,
+			v:    &Callback{},
+			got:  func(v any) Time { return v.(*Callback).EndDate },
+		},
+		{
+			name: "cash payment expired_date",
+			body: `{"expired_date":"2016-04-24 10:30:15"}`,
+			v:    &CashPaymentResponse{},
+			got:  func(v any) Time { return v.(*CashPaymentResponse).ExpiredDate },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.body), tt.v); err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", tt.body, err)
+			}
+			if got := tt.got(tt.v); !got.Time.Equal(want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.body, got.Time, want)
+			}
+		})
+	}
+}
+
+func TestTimeInRequests(t *testing.T) {
+	kyiv := time.FixedZone("EEST", 3*60*60)
+
+	tests := []struct {
+		name string
+		req  any
+		want string
+	}{
+		{
+			name: "invoice expired_date in utc",
+			req:  &InvoiceRequest{OrderID: "order-1", ExpiredDate: NewTime(time.Date(2016, 4, 24, 13, 30, 15, 0, kyiv))},
+			want: `"expired_date":"2016-04-24 10:30:15"`,
+		},
+		{
+			name: "subscription subscribe_date_start",
+			req:  &SubscriptionRequest{OrderID: "order-1", SubscribeDateStart: NewTime(time.Date(2016, 4, 24, 10, 30, 15, 0, time.UTC))},
+			want: `"subscribe_date_start":"2016-04-24 10:30:15"`,
+		},
+		{
+			name: "zero expired_date is omitted",
+			req:  &InvoiceRequest{OrderID: "order-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.req)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if tt.want == "" && strings.Contains(string(data), "_date") {
+				t.Errorf("Marshal() = %s, want no dates", data)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("Marshal() = %s, want it to contain %s", data, tt.want)
+			}
+		})
+	}
+}
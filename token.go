@@ -0,0 +1,35 @@
+package liqpay
+
+import "context"
+
+// PayWithToken charges a card previously saved as a card token.
+// Token-related financial errors are returned as *TokenError.
+func (c client) PayWithToken(data *TokenPaymentRequest) (*TokenPaymentResponse, error) {
+	return c.PayWithTokenContext(context.Background(), data)
+}
+
+// PayWithTokenContext charges a card token using the provided context.
+func (c client) PayWithTokenContext(ctx context.Context, data *TokenPaymentRequest) (*TokenPaymentResponse, error) {
+	data.Action = ActionPayToken
+
+	if err := data.Amount.Validate(data.Currency); err != nil {
+		return nil, err
+	}
+
+	req, err := c.prepareServerRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &TokenPaymentResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		apiErr, _ := ConvertToAPIError(err)
+		return v, newTokenError(apiErr)
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,128 @@
+package liqpay
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPaymentExpired is returned by WaitForStatus when the payment still awaits the customer after WaitOptions.ExpiredDate.
+var ErrPaymentExpired = errors.New("liqpay: payment expired while awaiting the customer")
+
+// WaitOptions configures WaitForStatus polling.
+type WaitOptions struct {
+	Interval         time.Duration              // Delay before the first status poll. Defaults to 1s
+	MaxInterval      time.Duration              // Upper bound of the delay between polls. Defaults to 30s
+	Multiplier       float64                    // Factor by which the delay grows after each poll. Defaults to 1.5
+	NotFoundWindow   time.Duration              // Period after the start during which payment_not_found means the payment is not created yet. Defaults to 5m
+	CashWaitInterval time.Duration              // Delay between polls while the payment is in cash_wait. Defaults to MaxInterval
+	ExpiredDate      time.Time                  // Stops polling with ErrPaymentExpired if the payment still awaits the customer after it, e.g. the expired_date of a cash payment
+	Until            func(*StatusResponse) bool // Stops polling when it returns true. Defaults to DefaultWaitUntil
+}
+
+// withDefaults returns a copy of the options with default values set.
+func (o *WaitOptions) withDefaults() WaitOptions {
+	var opts WaitOptions
+	if o != nil {
+		opts = *o
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 30 * time.Second
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 1.5
+	}
+	if opts.NotFoundWindow <= 0 {
+		opts.NotFoundWindow = 5 * time.Minute
+	}
+	if opts.CashWaitInterval <= 0 {
+		opts.CashWaitInterval = opts.MaxInterval
+	}
+	if opts.Until == nil {
+		opts.Until = DefaultWaitUntil
+	}
+
+	return opts
+}
+
+// DefaultWaitUntil reports whether the payment status will not change without further action of the merchant:
+// a final status, a successful one such as wait_compensation, which stays until the daily compensation,
+// or hold_wait, which stays until the hold is completed or canceled.
+func DefaultWaitUntil(s *StatusResponse) bool {
+	return s.Status.IsFinal() || s.Status.IsSuccessful() || s.Status == StatusHoldWait
+}
+
+// WaitForStatus polls the payment status with growing intervals until opts.Until returns true,
+// by default until DefaultWaitUntil does, and returns the last status response.
+//
+// During opts.NotFoundWindow the payment_not_found error is treated as the payment not being
+// created yet, e.g. the customer has not opened the checkout page. A payment that failed
+// in a final status, e.g. with a financial error, is returned together with its APIError.
+// Transient errors (see IsRetryableError) do not stop polling. If the context is done,
+// the last received status response is returned together with the context error.
+//
+// A cash payment stays in cash_wait until the customer pays at a terminal, so while in it the status
+// is polled every opts.CashWaitInterval. Set opts.ExpiredDate to the expired_date of the payment
+// to stop polling once the payment code has expired.
+//
+// By default polling stops at success and subscribed, which are final (see Status.IsFinal) but not
+// terminal: a later refund or unsubscription changes them, which is not observed by WaitForStatus.
+func (c client) WaitForStatus(ctx context.Context, orderID string, opts *WaitOptions) (*StatusResponse, error) {
+	var (
+		o        = opts.withDefaults()
+		start    = time.Now()
+		interval = o.Interval
+		last     *StatusResponse
+	)
+
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, contextError(ctx)
+		case <-timer.C:
+		}
+
+		v, err := c.StatusContext(ctx, orderID)
+		switch {
+		case err == nil:
+			last = v
+			if o.Until(v) {
+				return v, nil
+			}
+			if !o.ExpiredDate.IsZero() && v.Status.RequiresCustomerAction() && time.Now().After(o.ExpiredDate) {
+				return v, ErrPaymentExpired
+			}
+		case isPaymentNotFound(err) && time.Since(start) < o.NotFoundWindow:
+		case isFinalPayment(v):
+			return v, err
+		case IsRetryableError(err):
+		case ctx.Err() != nil:
+			return last, contextError(ctx)
+		default:
+			return v, err
+		}
+
+		if last != nil && last.Status == StatusCashWait {
+			interval = o.CashWaitInterval
+		} else {
+			interval = min(time.Duration(float64(interval)*o.Multiplier), o.MaxInterval)
+		}
+	}
+}
+
+// isFinalPayment reports whether the response of a failed status request describes a payment in a final status,
+// e.g. a payment declined with a financial error, rather than a failure of the request itself.
+func isFinalPayment(v *StatusResponse) bool {
+	return v != nil && v.PaymentID != 0 && v.Status.IsFinal()
+}
+
+// isPaymentNotFound reports whether the error is the payment_not_found API error.
+func isPaymentNotFound(err error) bool {
+	return errors.Is(err, ErrPaymentNotFound)
+}
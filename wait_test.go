@@ -0,0 +1,68 @@
+package liqpay_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kabachoksolutions/liqpay"
+	"github.com/kabachoksolutions/liqpay/liqpaytest"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      liqpay.Status
+		errCode     string
+		wantStatus  liqpay.Status
+		wantErr     error
+		wantErrCode string // err_code of the expected APIError
+	}{
+		{name: "success", status: liqpay.StatusSuccess, wantStatus: liqpay.StatusSuccess},
+		{name: "wait_compensation", status: liqpay.StatusWaitCompensation, wantStatus: liqpay.StatusWaitCompensation},
+		{name: "hold_wait", status: liqpay.StatusHoldWait, wantStatus: liqpay.StatusHoldWait},
+		{name: "reversed", status: liqpay.StatusReversed, wantStatus: liqpay.StatusReversed},
+		{name: "general error", status: liqpay.StatusFailure, errCode: "90", wantStatus: liqpay.StatusFailure, wantErrCode: "90"},
+		{name: "payment declined", status: liqpay.StatusFailure, errCode: "9854", wantStatus: liqpay.StatusFailure, wantErrCode: "9854"},
+		{name: "ip attempts limit", status: liqpay.StatusFailure, errCode: "109", wantStatus: liqpay.StatusFailure, wantErrCode: "109"},
+		{name: "processing", status: liqpay.StatusProcessing, wantStatus: liqpay.StatusProcessing, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := liqpaytest.NewServer("public", "private")
+			defer srv.Close()
+
+			srv.AddOrder(liqpaytest.Order{
+				OrderID:  "order-1",
+				Action:   liqpay.ActionPay,
+				Status:   tt.status,
+				Amount:   liqpay.MustParseAmount("100"),
+				Currency: liqpay.CurrencyUAH,
+				ErrCode:  tt.errCode,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			c := liqpay.NewClient(srv.Config(), nil)
+			v, err := c.WaitForStatus(ctx, "order-1", &liqpay.WaitOptions{Interval: time.Millisecond, MaxInterval: 10 * time.Millisecond})
+
+			switch apiErr, _ := liqpay.ConvertToAPIError(err); {
+			case tt.wantErrCode != "":
+				if apiErr == nil || apiErr.Code != tt.wantErrCode {
+					t.Fatalf("WaitForStatus() error = %v, want APIError with code %s", err, tt.wantErrCode)
+				}
+			case tt.wantErr == nil && err != nil, tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("WaitForStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if v == nil {
+				t.Fatalf("WaitForStatus() = nil, want status %s", tt.wantStatus)
+			}
+			if v.Status != tt.wantStatus {
+				t.Errorf("WaitForStatus() status = %s, want %s", v.Status, tt.wantStatus)
+			}
+		})
+	}
+}